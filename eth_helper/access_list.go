@@ -0,0 +1,120 @@
+package eth_helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrAccessListUnsupported 节点不支持 eth_createAccessList
+var ErrAccessListUnsupported = errors.New("eth_createAccessList is not supported by the node")
+
+// AccessListResult eth_createAccessList 的结果
+type AccessListResult struct {
+	AccessList types.AccessList
+	GasUsed    uint64 // 携带访问列表时估算的 gas
+	GasWithout uint64 // 不携带访问列表时估算的 gas
+}
+
+// GasSaved 使用访问列表节省的 gas，为负数时说明访问列表反而更贵
+func (r *AccessListResult) GasSaved() int64 {
+	return int64(r.GasWithout) - int64(r.GasUsed)
+}
+
+// CreateAccessList 调用 eth_createAccessList 生成 msg 访问的账户和存储槽，并分别估算携带和不携带访问列表时的 gas
+// 节点不支持该方法时返回 ErrAccessListUnsupported，调用回滚时返回 *revert.RevertError
+func (e *EthHelper) CreateAccessList(ctx context.Context, msg ethereum.CallMsg) (*AccessListResult, error) {
+	var res struct {
+		AccessList types.AccessList `json:"accessList"`
+		Error      string           `json:"error"`
+		GasUsed    hexutil.Uint64   `json:"gasUsed"`
+	}
+	if err := e.CallContext(ctx, &res, "eth_createAccessList", toCallArg(msg), "latest"); err != nil {
+		if isMethodNotFoundError(err) {
+			return nil, fmt.Errorf("%w: %v", ErrAccessListUnsupported, err)
+		}
+		return nil, e.decodeRevert(err)
+	}
+	if res.Error != "" {
+		return nil, e.decodeRevert(fmt.Errorf("failed to create access list: %s", res.Error))
+	}
+	msg.AccessList = nil
+	without, err := e.estimateCall(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	result := &AccessListResult{AccessList: res.AccessList, GasUsed: uint64(res.GasUsed), GasWithout: without}
+	// 节点返回的 gasUsed 是实际消耗，与 eth_estimateGas 的结果不完全可比，优先使用估算值
+	msg.AccessList = res.AccessList
+	if with, err := e.estimateCall(ctx, msg); err == nil {
+		result.GasUsed = with
+	}
+	return result, nil
+}
+
+// estimateCall 估算 msg 的 gasLimit
+func (e *EthHelper) estimateCall(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	gas, err := withClient(ctx, e, func(client *ethclient.Client) (uint64, error) {
+		return client.EstimateGas(ctx, msg)
+	})
+	return gas, e.decodeRevert(err)
+}
+
+// accessListFor 为交易生成访问列表，只在能节省 gas 时返回，节点不支持或生成失败时返回 nil
+func (e *EthHelper) accessListFor(ctx context.Context, msg ethereum.CallMsg) types.AccessList {
+	result, err := e.CreateAccessList(ctx, msg)
+	if err != nil || result.GasSaved() <= 0 {
+		return nil
+	}
+	return result.AccessList
+}
+
+// toCallArg 把 CallMsg 转换为 JSON-RPC 调用参数
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	if msg.GasFeeCap != nil {
+		arg["maxFeePerGas"] = (*hexutil.Big)(msg.GasFeeCap)
+	}
+	if msg.GasTipCap != nil {
+		arg["maxPriorityFeePerGas"] = (*hexutil.Big)(msg.GasTipCap)
+	}
+	if msg.AccessList != nil {
+		arg["accessList"] = msg.AccessList
+	}
+	return arg
+}
+
+// isMethodNotFoundError 判断错误是否为节点不支持该 RPC 方法
+func isMethodNotFoundError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601 {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "method not found") ||
+		strings.Contains(msg, "does not exist") ||
+		strings.Contains(msg, "not supported") ||
+		strings.Contains(msg, "unsupported method")
+}
@@ -0,0 +1,118 @@
+package eth_helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+)
+
+var testAccessList = types.AccessList{{
+	Address:     common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"),
+	StorageKeys: []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02")},
+}}
+
+// newAccessListServer 模拟节点：eth_createAccessList 返回 createResult，为空时节点不支持该方法，
+// eth_estimateGas 携带访问列表时返回 withGas，否则返回 withoutGas
+func newAccessListServer(t *testing.T, createResult string, withGas, withoutGas uint64) *httptest.Server {
+	t.Helper()
+	createAccessList := rpctest.Fail(rpctest.MethodNotFound("eth_createAccessList"))
+	if createResult != "" {
+		createAccessList = rpctest.Result(json.RawMessage(createResult))
+	}
+	return rpctest.NewServer(t, rpctest.Handlers{
+		"eth_createAccessList": createAccessList,
+		"eth_estimateGas": func(params []json.RawMessage) (interface{}, error) {
+			if args := rpctest.ParseCall(params); len(args.AccessList) > 0 {
+				return hexutil.Uint64(withGas), nil
+			}
+			return hexutil.Uint64(withoutGas), nil
+		},
+		"eth_chainId":    rpctest.Result("0x1"),
+		"eth_getBalance": rpctest.Result("0xde0b6b3a7640000"),
+	})
+}
+
+func accessListResultJSON(gasUsed uint64) string {
+	list, _ := json.Marshal(testAccessList)
+	return fmt.Sprintf(`{"accessList":%s,"gasUsed":"0x%x"}`, list, gasUsed)
+}
+
+func TestEthHelper_CreateAccessList(t *testing.T) {
+	to := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	msg := ethereum.CallMsg{From: common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768"), To: &to, Data: []byte{0xa9, 0x05, 0x9c, 0xbb}}
+
+	eth := NewEthHelper(newAccessListServer(t, accessListResultJSON(45000), 46000, 48000).URL)
+	defer eth.Close()
+	result, err := eth.CreateAccessList(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("CreateAccessList() error = %v", err)
+	}
+	if len(result.AccessList) != 1 || len(result.AccessList[0].StorageKeys) != 2 {
+		t.Errorf("CreateAccessList() access list = %v, want %v", result.AccessList, testAccessList)
+	}
+	if result.GasUsed != 46000 || result.GasWithout != 48000 || result.GasSaved() != 2000 {
+		t.Errorf("CreateAccessList() gas = %d/%d saved %d, want 46000/48000 saved 2000", result.GasUsed, result.GasWithout, result.GasSaved())
+	}
+
+	eth2 := NewEthHelper(newAccessListServer(t, "", 0, 21000).URL)
+	defer eth2.Close()
+	if _, err := eth2.CreateAccessList(context.Background(), msg); !errors.Is(err, ErrAccessListUnsupported) {
+		t.Errorf("CreateAccessList() error = %v, want ErrAccessListUnsupported", err)
+	}
+
+	eth3 := NewEthHelper(newAccessListServer(t, `{"accessList":[],"gasUsed":"0x0","error":"execution reverted"}`, 0, 21000).URL)
+	defer eth3.Close()
+	if _, err := eth3.CreateAccessList(context.Background(), msg); err == nil || errors.Is(err, ErrAccessListUnsupported) {
+		t.Errorf("CreateAccessList() error = %v, want execution reverted", err)
+	}
+}
+
+func TestEthHelper_BuildUnsignedAccessList(t *testing.T) {
+	from := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	to := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	nonce := uint64(3)
+	tests := []struct {
+		name         string
+		createResult string // eth_createAccessList 的结果，为空时节点不支持该方法
+		withGas      uint64
+		withoutGas   uint64
+		params       TxParams
+		wantType     uint8
+		wantList     int
+		wantGasLim   uint64
+	}{
+		{"auto access list saves gas", accessListResultJSON(45000), 46000, 48000, TxParams{TxType: TxTypeLegacy, AutoAccessList: true}, types.AccessListTxType, 1, 46000},
+		{"auto access list costs more", accessListResultJSON(45000), 49000, 48000, TxParams{TxType: TxTypeLegacy, AutoAccessList: true}, types.LegacyTxType, 0, 48000},
+		{"node does not support eth_createAccessList", "", 0, 48000, TxParams{TxType: TxTypeLegacy, AutoAccessList: true}, types.LegacyTxType, 0, 48000},
+		{"explicit access list", "", 46000, 48000, TxParams{TxType: TxTypeLegacy, AccessList: testAccessList}, types.AccessListTxType, 1, 46000},
+		{"access list type without list", "", 0, 48000, TxParams{TxType: TxTypeAccessList}, types.AccessListTxType, 0, 48000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eth := NewEthHelper(newAccessListServer(t, tt.createResult, tt.withGas, tt.withoutGas).URL)
+			defer eth.Close()
+			params := tt.params
+			params.From, params.To, params.Amount, params.Nonce, params.GasPrice = from, &to, decimal.Zero, &nonce, big.NewInt(1e9)
+			unsigned, err := eth.BuildUnsigned(context.Background(), params)
+			if err != nil {
+				t.Fatalf("BuildUnsigned() error = %v", err)
+			}
+			tx := unsigned.Tx
+			if tx.Type() != tt.wantType || len(tx.AccessList()) != tt.wantList || tx.Gas() != tt.wantGasLim {
+				t.Errorf("BuildUnsigned() type %d, access list %d, gas %d, want %d, %d, %d",
+					tx.Type(), len(tx.AccessList()), tx.Gas(), tt.wantType, tt.wantList, tt.wantGasLim)
+			}
+		})
+	}
+}
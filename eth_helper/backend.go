@@ -0,0 +1,82 @@
+package eth_helper
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// contractBackend 把 EthHelper 适配为 abigen 绑定所需的 bind.ContractBackend，
+// 使合约调用同样复用 EthHelper 持有的连接
+type contractBackend struct {
+	e *EthHelper
+}
+
+// ContractBackend 返回基于共享连接的 bind.ContractBackend，供合约绑定使用
+func (e *EthHelper) ContractBackend() bind.ContractBackend {
+	return &contractBackend{e: e}
+}
+
+func (b *contractBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return b.e.CodeAt(ctx, contract, blockNumber)
+}
+
+func (b *contractBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return b.e.CallContract(ctx, call, blockNumber)
+}
+
+func (b *contractBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return withClient(ctx, b.e, func(client *ethclient.Client) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, number)
+	})
+}
+
+func (b *contractBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return withClient(ctx, b.e, func(client *ethclient.Client) ([]byte, error) {
+		return client.PendingCodeAt(ctx, account)
+	})
+}
+
+func (b *contractBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return withClient(ctx, b.e, func(client *ethclient.Client) (uint64, error) {
+		return client.PendingNonceAt(ctx, account)
+	})
+}
+
+func (b *contractBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return withClient(ctx, b.e, func(client *ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasPrice(ctx)
+	})
+}
+
+func (b *contractBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return withClient(ctx, b.e, func(client *ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasTipCap(ctx)
+	})
+}
+
+func (b *contractBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return withClient(ctx, b.e, func(client *ethclient.Client) (uint64, error) {
+		return client.EstimateGas(ctx, call)
+	})
+}
+
+func (b *contractBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := b.e.SendTransaction(ctx, tx)
+	return err
+}
+
+func (b *contractBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return b.e.FilterLogs(ctx, query)
+}
+
+func (b *contractBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return withClient(ctx, b.e, func(client *ethclient.Client) (ethereum.Subscription, error) {
+		return client.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
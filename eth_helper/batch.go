@@ -0,0 +1,191 @@
+package eth_helper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/utils"
+)
+
+// DefaultBatchSize 单个 JSON-RPC 批量请求默认包含的请求数
+const DefaultBatchSize = 100
+
+// BalanceResult 批量查询余额的单项结果
+type BalanceResult struct {
+	Address common.Address
+	Balance decimal.Decimal // 单位 ETH
+	Err     error
+}
+
+// NonceResult 批量查询 nonce 的单项结果
+type NonceResult struct {
+	Address common.Address
+	Nonce   uint64 // pending 状态下的 nonce
+	Err     error
+}
+
+// TransactionReceiptResult 批量查询交易回执的单项结果，交易未上链时 Err 为 ethereum.NotFound
+type TransactionReceiptResult struct {
+	TxHash  common.Hash
+	Receipt *types.Receipt
+	Err     error
+}
+
+// BlockResult 批量查询区块的单项结果，区块不存在时 Err 为 ethereum.NotFound
+type BlockResult struct {
+	Number uint64
+	Block  *types.Block
+	Err    error
+}
+
+// SetBatchSize 设置单个批量请求包含的请求数，超过时自动拆分为多个批量请求
+func (e *EthHelper) SetBatchSize(size int) {
+	e.batchSize = size
+}
+
+// batchCall 按批量大小拆分后发送批量请求，单项的错误写入对应 BatchElem.Error
+func (e *EthHelper) batchCall(ctx context.Context, elems []rpc.BatchElem) error {
+	size := e.batchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	for start := 0; start < len(elems); start += size {
+		end := start + size
+		if end > len(elems) {
+			end = len(elems)
+		}
+		chunk := elems[start:end]
+		_, err := withClient(ctx, e, func(client *ethclient.Client) (struct{}, error) {
+			return struct{}{}, client.Client().BatchCallContext(ctx, chunk)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchGetBalance 批量查询地址余额，单个地址失败不影响其他地址
+func (e *EthHelper) BatchGetBalance(ctx context.Context, addresses []common.Address) ([]BalanceResult, error) {
+	balances := make([]hexutil.Big, len(addresses))
+	elems := make([]rpc.BatchElem, len(addresses))
+	for i, address := range addresses {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []interface{}{address, "latest"},
+			Result: &balances[i],
+		}
+	}
+	if err := e.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+	results := make([]BalanceResult, len(addresses))
+	for i, address := range addresses {
+		results[i] = BalanceResult{Address: address, Err: elems[i].Error}
+		if elems[i].Error == nil {
+			results[i].Balance = utils.FromEther((*big.Int)(&balances[i]))
+		}
+	}
+	return results, nil
+}
+
+// BatchGetNonces 批量查询地址 pending 状态下的 nonce
+func (e *EthHelper) BatchGetNonces(ctx context.Context, addresses []common.Address) ([]NonceResult, error) {
+	nonces := make([]hexutil.Uint64, len(addresses))
+	elems := make([]rpc.BatchElem, len(addresses))
+	for i, address := range addresses {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionCount",
+			Args:   []interface{}{address, "pending"},
+			Result: &nonces[i],
+		}
+	}
+	if err := e.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+	results := make([]NonceResult, len(addresses))
+	for i, address := range addresses {
+		results[i] = NonceResult{Address: address, Nonce: uint64(nonces[i]), Err: elems[i].Error}
+	}
+	return results, nil
+}
+
+// BatchGetTransactionReceipts 批量查询交易回执
+func (e *EthHelper) BatchGetTransactionReceipts(ctx context.Context, txHashes []common.Hash) ([]TransactionReceiptResult, error) {
+	receipts := make([]*types.Receipt, len(txHashes))
+	elems := make([]rpc.BatchElem, len(txHashes))
+	for i, txHash := range txHashes {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{txHash},
+			Result: &receipts[i],
+		}
+	}
+	if err := e.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+	results := make([]TransactionReceiptResult, len(txHashes))
+	for i, txHash := range txHashes {
+		results[i] = TransactionReceiptResult{TxHash: txHash, Receipt: receipts[i], Err: elems[i].Error}
+		if results[i].Err == nil && receipts[i] == nil {
+			results[i].Err = ethereum.NotFound
+		}
+	}
+	return results, nil
+}
+
+// BatchGetBlocks 批量查询区块及其交易，不包含叔块
+func (e *EthHelper) BatchGetBlocks(ctx context.Context, numbers []uint64) ([]BlockResult, error) {
+	raws := make([]json.RawMessage, len(numbers))
+	elems := make([]rpc.BatchElem, len(numbers))
+	for i, number := range numbers {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeUint64(number), true},
+			Result: &raws[i],
+		}
+	}
+	if err := e.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+	results := make([]BlockResult, len(numbers))
+	for i, number := range numbers {
+		results[i] = BlockResult{Number: number, Err: elems[i].Error}
+		if results[i].Err != nil {
+			continue
+		}
+		results[i].Block, results[i].Err = decodeBlock(raws[i])
+	}
+	return results, nil
+}
+
+// decodeBlock 解析 eth_getBlockByNumber 返回的区块 JSON
+func decodeBlock(raw json.RawMessage) (*types.Block, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, ethereum.NotFound
+	}
+	var header types.Header
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode block header: %v", err)
+	}
+	var body struct {
+		Transactions []*types.Transaction `json:"transactions"`
+		Withdrawals  []*types.Withdrawal  `json:"withdrawals,omitempty"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf("failed to decode block body: %v", err)
+	}
+	return types.NewBlockWithHeader(&header).WithBody(types.Body{
+		Transactions: body.Transactions,
+		Withdrawals:  body.Withdrawals,
+	}), nil
+}
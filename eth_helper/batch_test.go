@@ -0,0 +1,64 @@
+package eth_helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+)
+
+func TestEthHelper_BatchGetBalance(t *testing.T) {
+	bad := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	var batches int32
+	handler := rpctest.NewHandler(rpctest.Handlers{
+		"eth_getBalance": func(params []json.RawMessage) (interface{}, error) {
+			var address common.Address
+			_ = json.Unmarshal(params[0], &address)
+			if address == bad {
+				return nil, errors.New("invalid address")
+			}
+			return "0xde0b6b3a7640000", nil
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&batches, 1)
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	e := NewEthHelper(server.URL)
+	defer e.Close()
+	e.SetBatchSize(2)
+	addresses := []common.Address{
+		common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768"),
+		bad,
+		common.HexToAddress("0xE837C72A310F201AD2E3CA4E44C1DD0D41F4EC8B"),
+	}
+	results, err := e.BatchGetBalance(context.Background(), addresses)
+	if err != nil {
+		t.Fatalf("BatchGetBalance() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&batches); got != 2 {
+		t.Errorf("BatchGetBalance() sent %v batches, want 2", got)
+	}
+	for i, result := range results {
+		if result.Address != addresses[i] {
+			t.Errorf("results[%d].Address = %v, want %v", i, result.Address, addresses[i])
+		}
+		if addresses[i] == bad {
+			if result.Err == nil {
+				t.Errorf("results[%d].Err = nil, want error", i)
+			}
+			continue
+		}
+		if result.Err != nil || result.Balance.String() != "1" {
+			t.Errorf("results[%d] = %v, %v, want 1, nil", i, result.Balance, result.Err)
+		}
+	}
+}
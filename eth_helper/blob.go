@@ -0,0 +1,219 @@
+package eth_helper
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/utils"
+)
+
+// BlobDataSize 单个 blob 可以携带的数据字节数
+// 每个 32 字节的域元素只使用低 31 字节，保证小于 BLS12-381 的模数
+const BlobDataSize = params.BlobTxFieldElementsPerBlob * (params.BlobTxBytesPerFieldElement - 1)
+
+var (
+	// ErrNoBlobs blob 交易至少需要一个 blob
+	ErrNoBlobs = errors.New("blob transaction requires at least one blob")
+	// ErrBlobTxRecipient blob 交易不能用于创建合约
+	ErrBlobTxRecipient = errors.New("blob transaction requires a recipient")
+)
+
+// EncodeBlobs 把任意数据按 BlobDataSize 切分并编码为 blob，最后一个 blob 不足的部分补 0
+func EncodeBlobs(data []byte) []kzg4844.Blob {
+	blobs := make([]kzg4844.Blob, (len(data)+BlobDataSize-1)/BlobDataSize)
+	for i := range blobs {
+		chunk := data[i*BlobDataSize:]
+		for j := 0; j < params.BlobTxFieldElementsPerBlob && len(chunk) > 0; j++ {
+			// 域元素首字节保持为 0
+			n := copy(blobs[i][j*params.BlobTxBytesPerFieldElement+1:(j+1)*params.BlobTxBytesPerFieldElement], chunk)
+			chunk = chunk[n:]
+		}
+	}
+	return blobs
+}
+
+// DecodeBlobs 还原 EncodeBlobs 编码的数据，结果包含末尾补的 0，数据长度需要调用方自行记录
+func DecodeBlobs(blobs []kzg4844.Blob) []byte {
+	data := make([]byte, 0, len(blobs)*BlobDataSize)
+	for i := range blobs {
+		for j := 0; j < params.BlobTxFieldElementsPerBlob; j++ {
+			data = append(data, blobs[i][j*params.BlobTxBytesPerFieldElement+1:(j+1)*params.BlobTxBytesPerFieldElement]...)
+		}
+	}
+	return data
+}
+
+// NewBlobSidecar 计算每个 blob 的 KZG 承诺和证明，生成交易的 sidecar
+func NewBlobSidecar(blobs []kzg4844.Blob) (*types.BlobTxSidecar, error) {
+	if len(blobs) == 0 {
+		return nil, ErrNoBlobs
+	}
+	sidecar := &types.BlobTxSidecar{
+		Blobs:       blobs,
+		Commitments: make([]kzg4844.Commitment, len(blobs)),
+		Proofs:      make([]kzg4844.Proof, len(blobs)),
+	}
+	for i := range blobs {
+		commitment, err := kzg4844.BlobToCommitment(&blobs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute commitment of blob %d: %v", i, err)
+		}
+		proof, err := kzg4844.ComputeBlobProof(&blobs[i], commitment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute proof of blob %d: %v", i, err)
+		}
+		sidecar.Commitments[i], sidecar.Proofs[i] = commitment, proof
+	}
+	return sidecar, nil
+}
+
+// BlobHash 计算 KZG 承诺的版本化哈希：0x01 || sha256(commitment)[1:]
+func BlobHash(commitment kzg4844.Commitment) common.Hash {
+	return kzg4844.CalcBlobHashV1(sha256.New(), &commitment)
+}
+
+// BlobHashes 按顺序计算多个 KZG 承诺的版本化哈希
+func BlobHashes(commitments []kzg4844.Commitment) []common.Hash {
+	hashes := make([]common.Hash, len(commitments))
+	for i := range commitments {
+		hashes[i] = BlobHash(commitments[i])
+	}
+	return hashes
+}
+
+// BlobBaseFee 返回下一个区块的 blobBaseFee
+func (e *EthHelper) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	return withClient(ctx, e, func(client *ethclient.Client) (*big.Int, error) {
+		return client.BlobBaseFee(ctx)
+	})
+}
+
+// SuggestBlobGasFeeCap 建议的 maxFeePerBlobGas = 2 * blobBaseFee，可以承受连续几个区块 blobBaseFee 上涨
+func (e *EthHelper) SuggestBlobGasFeeCap(ctx context.Context) (*big.Int, error) {
+	baseFee, err := e.BlobBaseFee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+	if feeCap.Sign() == 0 {
+		feeCap.SetInt64(1)
+	}
+	return feeCap, nil
+}
+
+// SendBlobTransaction 构造、签名并发送携带 sidecar 的 EIP-4844 交易，opts.Signer 必须设置
+// 手续费总是使用 EIP-1559，opts.TxType 和 opts.AutoAccessList 被忽略，其余字段含义与 Transact 相同
+// 发送后会缓存最近的 sidecar，SpeedUp 和 Cancel 只能替换同一个 EthHelper 最近发送的 blob 交易
+func (e *EthHelper) SendBlobTransaction(ctx context.Context, to common.Address, amount decimal.Decimal, data []byte, sidecar *types.BlobTxSidecar, opts TxOptions) (common.Hash, error) {
+	if opts.Signer == nil {
+		return common.Hash{}, ErrMissingSigner
+	}
+	unsigned, managed, err := e.buildBlobTx(ctx, opts.Signer.Address(), to, amount, data, sidecar, opts)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return e.signAndSend(ctx, opts.Signer, unsigned, managed)
+}
+
+// BuildUnsignedBlob 在线构造携带 sidecar 的未签名 EIP-4844 交易，params.To 必须设置，params.TxType 被忽略
+func (e *EthHelper) BuildUnsignedBlob(ctx context.Context, params TxParams, sidecar *types.BlobTxSidecar) (*UnsignedTx, error) {
+	if params.To == nil {
+		return nil, ErrBlobTxRecipient
+	}
+	unsigned, _, err := e.buildBlobTx(ctx, params.From, *params.To, params.Amount, params.Data, sidecar, TxOptions{
+		GasLimit:      params.GasLimit,
+		GasPrice:      params.GasPrice,
+		GasTipCap:     params.GasTipCap,
+		Nonce:         params.Nonce,
+		AccessList:    params.AccessList,
+		BlobGasFeeCap: params.BlobGasFeeCap,
+	})
+	return unsigned, err
+}
+
+// buildBlobTx 确定 gasLimit、手续费、chainId 和 nonce，构造未签名的 BlobTx
+func (e *EthHelper) buildBlobTx(ctx context.Context, from, to common.Address, amount decimal.Decimal, data []byte, sidecar *types.BlobTxSidecar, opts TxOptions) (unsigned *UnsignedTx, managed bool, err error) {
+	if sidecar == nil || len(sidecar.Blobs) == 0 {
+		return nil, false, ErrNoBlobs
+	}
+	if len(sidecar.Commitments) != len(sidecar.Blobs) || len(sidecar.Proofs) != len(sidecar.Blobs) {
+		return nil, false, fmt.Errorf("invalid sidecar: %d blobs, %d commitments, %d proofs", len(sidecar.Blobs), len(sidecar.Commitments), len(sidecar.Proofs))
+	}
+	// 1. 计算手续费
+	fee, err := e.dynamicFeeFor(ctx, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	blobFeeCap := opts.BlobGasFeeCap
+	if blobFeeCap == nil {
+		blobFeeCap, err = e.SuggestBlobGasFeeCap(ctx)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to suggest blob gas fee cap: %v", err)
+		}
+	}
+	// 2. 估算 gasLimit，blob 本身消耗的是 blob gas，不计入 gasLimit
+	value := utils.ToEther(amount)
+	blobHashes := sidecar.BlobHashes()
+	gasLimit, err := e.estimateCall(ctx, ethereum.CallMsg{
+		From:          from,
+		To:            &to,
+		Data:          data,
+		Value:         value,
+		AccessList:    opts.AccessList,
+		BlobGasFeeCap: blobFeeCap,
+		BlobHashes:    blobHashes,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	if opts.GasLimit > gasLimit {
+		gasLimit = opts.GasLimit
+	}
+	// 3. 获取 chainID 并检查余额，余额需要覆盖 gas 和 blob gas 两部分手续费
+	chainID, err := e.GetChainId(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	blobGas := new(big.Int).SetUint64(uint64(len(blobHashes)) * params.BlobTxBlobGasPerBlob)
+	blobFee := utils.FromEther(blobGas.Mul(blobGas, blobFeeCap))
+	if _, err = e.checkBalance(ctx, from, amount.Add(blobFee), gasLimit, fee.GasFeeCap); err != nil {
+		return nil, false, err
+	}
+	// 4. 分配 nonce
+	nonce := opts.Nonce
+	managed = nonce == nil
+	if managed {
+		n, err := e.NonceManager().Next(ctx, from)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get nonce: %v", err)
+		}
+		nonce = &n
+	}
+	// 5. 创建交易对象，签名时 LatestSignerForChainID 会选择支持 BlobTx 的 Cancun 签名器
+	txData := &types.BlobTx{
+		ChainID:    uint256.MustFromBig(chainID),
+		Nonce:      *nonce,
+		GasTipCap:  uint256.MustFromBig(fee.GasTipCap),
+		GasFeeCap:  uint256.MustFromBig(fee.GasFeeCap),
+		Gas:        gasLimit,
+		To:         to,
+		Value:      uint256.MustFromBig(value),
+		Data:       data,
+		AccessList: opts.AccessList,
+		BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+		BlobHashes: blobHashes,
+		Sidecar:    sidecar,
+	}
+	return &UnsignedTx{From: from, ChainID: chainID, Tx: types.NewTx(txData)}, managed, nil
+}
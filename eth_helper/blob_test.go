@@ -0,0 +1,206 @@
+package eth_helper
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+	"github.com/web3coderecho/web3_helper/eth_helper/signer"
+)
+
+func TestEncodeBlobs(t *testing.T) {
+	data := bytes.Repeat([]byte{0xff, 0x01, 0x02}, BlobDataSize/3+100)
+	blobs := EncodeBlobs(data)
+	if len(blobs) != 2 {
+		t.Fatalf("EncodeBlobs() = %d blobs, want 2", len(blobs))
+	}
+	for i := range blobs {
+		for j := 0; j < len(blobs[i]); j += 32 {
+			if blobs[i][j] != 0 {
+				t.Fatalf("EncodeBlobs() blob %d field element %d high byte = %#x, want 0", i, j/32, blobs[i][j])
+			}
+		}
+	}
+	decoded := DecodeBlobs(blobs)
+	if len(decoded) != 2*BlobDataSize || !bytes.Equal(decoded[:len(data)], data) || bytes.ContainsFunc(decoded[len(data):], func(r rune) bool { return r != 0 }) {
+		t.Errorf("DecodeBlobs() did not restore the encoded data")
+	}
+	if blobs := EncodeBlobs(nil); len(blobs) != 0 {
+		t.Errorf("EncodeBlobs(nil) = %d blobs, want 0", len(blobs))
+	}
+}
+
+func TestNewBlobSidecar(t *testing.T) {
+	sidecar, err := NewBlobSidecar(EncodeBlobs([]byte("rollup batch")))
+	if err != nil {
+		t.Fatalf("NewBlobSidecar() error = %v", err)
+	}
+	if err := kzg4844.VerifyBlobProof(&sidecar.Blobs[0], sidecar.Commitments[0], sidecar.Proofs[0]); err != nil {
+		t.Errorf("NewBlobSidecar() proof does not verify: %v", err)
+	}
+	hashes := BlobHashes(sidecar.Commitments)
+	digest := sha256.Sum256(sidecar.Commitments[0][:])
+	if hashes[0][0] != 0x01 || !bytes.Equal(hashes[0][1:], digest[1:]) || !kzg4844.IsValidVersionedHash(hashes[0][:]) {
+		t.Errorf("BlobHashes() = %s, want 0x01 || sha256(commitment)[1:]", hashes[0].Hex())
+	}
+	if err := sidecar.ValidateBlobCommitmentHashes(hashes); err != nil {
+		t.Errorf("BlobHashes() do not match sidecar: %v", err)
+	}
+	if _, err := NewBlobSidecar(nil); !errors.Is(err, ErrNoBlobs) {
+		t.Errorf("NewBlobSidecar(nil) error = %v, want ErrNoBlobs", err)
+	}
+}
+
+// newBlobServer 模拟支持 Cancun 的节点，baseFee 和 tip 为 1 gwei，blobBaseFee 为 blobBaseFee
+// estimated 记录 eth_estimateGas 是否携带了 blob 哈希和 maxFeePerBlobGas
+func newBlobServer(t *testing.T, blobBaseFee string, estimated *bool) *httptest.Server {
+	t.Helper()
+	return rpctest.NewServer(t, rpctest.Handlers{
+		"eth_feeHistory":  rpctest.Result(json.RawMessage(`{"oldestBlock":"0x1","baseFeePerGas":["0x3b9aca00","0x3b9aca00"],"gasUsedRatio":[0.5],"reward":[["0x3b9aca00","0x3b9aca00"]]}`)),
+		"eth_blobBaseFee": rpctest.Result(blobBaseFee),
+		"eth_estimateGas": func(params []json.RawMessage) (interface{}, error) {
+			args := rpctest.ParseCall(params)
+			*estimated = len(args.BlobHashes) == 1 && args.BlobGasFeeCap != nil
+			return "0x5208", nil
+		},
+		"eth_chainId":    rpctest.Result("0xaa36a7"),
+		"eth_getBalance": rpctest.Result("0xde0b6b3a7640000"),
+	})
+}
+
+func TestEthHelper_BuildUnsignedBlob(t *testing.T) {
+	key, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	to := common.HexToAddress("0xFF00000000000000000000000000000000011155")
+	sidecar, err := NewBlobSidecar(EncodeBlobs([]byte("rollup batch")))
+	if err != nil {
+		t.Fatalf("NewBlobSidecar() error = %v", err)
+	}
+	var estimated bool
+	eth := NewEthHelper(newBlobServer(t, "0x3", &estimated).URL)
+	defer eth.Close()
+
+	nonce := uint64(5)
+	params := TxParams{From: key.Address(), To: &to, Amount: decimal.Zero, Nonce: &nonce}
+	unsigned, err := eth.BuildUnsignedBlob(context.Background(), params, sidecar)
+	if err != nil {
+		t.Fatalf("BuildUnsignedBlob() error = %v", err)
+	}
+	tx := unsigned.Tx
+	if tx.Type() != types.BlobTxType || tx.BlobGasFeeCap().Cmp(big.NewInt(6)) != 0 || tx.Gas() != 21000 || tx.Nonce() != 5 {
+		t.Errorf("BuildUnsignedBlob() type %d, blob fee cap %s, gas %d, nonce %d, want 3, 6, 21000, 5",
+			tx.Type(), tx.BlobGasFeeCap(), tx.Gas(), tx.Nonce())
+	}
+	if !estimated {
+		t.Errorf("BuildUnsignedBlob() did not estimate gas with blob hashes and maxFeePerBlobGas")
+	}
+	if hashes := tx.BlobHashes(); len(hashes) != 1 || hashes[0] != BlobHash(sidecar.Commitments[0]) {
+		t.Errorf("BuildUnsignedBlob() blob hashes = %v", hashes)
+	}
+
+	signedTx, err := SignOffline(unsigned, key)
+	if err != nil {
+		t.Fatalf("SignOffline() error = %v", err)
+	}
+	raw, _ := signedTx.MarshalBinary()
+	decoded := new(types.Transaction)
+	if err := decoded.UnmarshalBinary(raw); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if decoded.BlobTxSidecar() == nil || len(decoded.BlobTxSidecar().Blobs) != 1 {
+		t.Errorf("signed transaction lost its sidecar")
+	}
+	sender, err := types.Sender(types.NewCancunSigner(unsigned.ChainID), decoded)
+	if err != nil || sender != key.Address() {
+		t.Errorf("Cancun signer sender = %s, %v, want %s", sender.Hex(), err, key.Address().Hex())
+	}
+
+	params.BlobGasFeeCap = big.NewInt(100)
+	if unsigned, err := eth.BuildUnsignedBlob(context.Background(), params, sidecar); err != nil || unsigned.Tx.BlobGasFeeCap().Int64() != 100 {
+		t.Errorf("BuildUnsignedBlob() with BlobGasFeeCap = %v, want 100", err)
+	}
+	params.To = nil
+	if _, err := eth.BuildUnsignedBlob(context.Background(), params, sidecar); !errors.Is(err, ErrBlobTxRecipient) {
+		t.Errorf("BuildUnsignedBlob() without recipient error = %v, want ErrBlobTxRecipient", err)
+	}
+}
+
+func TestEthHelper_SpeedUpBlob(t *testing.T) {
+	key, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	to := common.HexToAddress("0xFF00000000000000000000000000000000011155")
+	sidecar, err := NewBlobSidecar(EncodeBlobs([]byte("rollup batch")))
+	if err != nil {
+		t.Fatalf("NewBlobSidecar() error = %v", err)
+	}
+	var sent []*types.Transaction
+	handlers := rpctest.Handlers{
+		"eth_feeHistory":          rpctest.Result(json.RawMessage(`{"oldestBlock":"0x1","baseFeePerGas":["0x3b9aca00","0x3b9aca00"],"gasUsedRatio":[0.5],"reward":[["0x3b9aca00","0x3b9aca00"]]}`)),
+		"eth_blobBaseFee":         rpctest.Result("0x3"),
+		"eth_estimateGas":         rpctest.Result("0x5208"),
+		"eth_chainId":             rpctest.Result("0xaa36a7"),
+		"eth_getBalance":          rpctest.Result("0xde0b6b3a7640000"),
+		"eth_getTransactionCount": rpctest.Result("0x7"),
+		"eth_sendRawTransaction": func(params []json.RawMessage) (interface{}, error) {
+			var raw hexutil.Bytes
+			_ = json.Unmarshal(params[0], &raw)
+			tx := new(types.Transaction)
+			if err := tx.UnmarshalBinary(raw); err != nil {
+				return nil, err
+			}
+			sent = append(sent, tx)
+			return tx.Hash(), nil
+		},
+		// 与节点一致，返回的待打包 blob 交易不带 sidecar
+		"eth_getTransactionByHash": func(params []json.RawMessage) (interface{}, error) {
+			return sent[len(sent)-1].WithoutBlobTxSidecar(), nil
+		},
+	}
+	eth := NewEthHelper(rpctest.NewServer(t, handlers).URL)
+	defer eth.Close()
+
+	hash, err := eth.SendBlobTransaction(context.Background(), to, decimal.Zero, nil, sidecar, TxOptions{Signer: key})
+	if err != nil {
+		t.Fatalf("SendBlobTransaction() error = %v", err)
+	}
+	newHash, err := eth.SpeedUp(context.Background(), hash, 1.1, key)
+	if err != nil {
+		t.Fatalf("SpeedUp() error = %v", err)
+	}
+	if len(sent) != 2 || sent[1].Hash() != newHash {
+		t.Fatalf("SpeedUp() sent %d transactions, returned %s", len(sent), newHash)
+	}
+	old, replaced := sent[0], sent[1]
+	if replaced.Type() != types.BlobTxType || replaced.Nonce() != old.Nonce() || replaced.BlobTxSidecar() == nil {
+		t.Fatalf("SpeedUp() type %d, nonce %d, sidecar %v, want blob transaction with nonce %d and sidecar", replaced.Type(), replaced.Nonce(), replaced.BlobTxSidecar() != nil, old.Nonce())
+	}
+	// blobpool 要求 tip、feeCap 和 blobFeeCap 都至少翻倍
+	for _, fee := range []struct {
+		name     string
+		old, new *big.Int
+	}{
+		{"gas tip cap", old.GasTipCap(), replaced.GasTipCap()},
+		{"gas fee cap", old.GasFeeCap(), replaced.GasFeeCap()},
+		{"blob fee cap", old.BlobGasFeeCap(), replaced.BlobGasFeeCap()},
+	} {
+		if minimum := new(big.Int).Mul(fee.old, big.NewInt(2)); fee.new.Cmp(minimum) <= 0 {
+			t.Errorf("SpeedUp() %s = %s, want more than %s", fee.name, fee.new, minimum)
+		}
+	}
+
+	// 其他实例没有缓存 sidecar，无法替换
+	other := NewEthHelper(rpctest.NewServer(t, handlers).URL)
+	defer other.Close()
+	if _, err := other.Cancel(context.Background(), newHash, key); !errors.Is(err, ErrMissingBlobSidecar) {
+		t.Errorf("Cancel() error = %v, want ErrMissingBlobSidecar", err)
+	}
+}
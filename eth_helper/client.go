@@ -0,0 +1,178 @@
+package eth_helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// dialEthClient 连接到指定的以太坊节点
+func dialEthClient(ctx context.Context, url string) (*ethclient.Client, error) {
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Ethereum node: %v", err)
+	}
+	return client, nil
+}
+
+// GetClient 返回 EthHelper 持有的共享客户端，首次调用时才建立连接
+// 配置了多个节点时返回当前优先使用的节点，返回的客户端由 EthHelper 管理，调用方不要关闭它
+func (e *EthHelper) GetClient(ctx context.Context) (*ethclient.Client, error) {
+	var lastErr error
+	for _, ep := range e.readOrder() {
+		client, err := ep.getClient(ctx)
+		if err == nil {
+			return client, nil
+		}
+		ep.record(0, false)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// Close 关闭所有节点的连接并停止后台健康检查，之后的调用会重新建立连接
+func (e *EthHelper) Close() {
+	e.mu.Lock()
+	if e.stopHealth != nil {
+		close(e.stopHealth)
+		e.stopHealth = nil
+	}
+	endpoints := e.endpoints
+	e.mu.Unlock()
+	for _, ep := range endpoints {
+		ep.close()
+	}
+}
+
+// CallContext 发送任意 JSON-RPC 请求，用于 ethclient 没有封装的方法，调用同样经过中间件和节点切换
+func (e *EthHelper) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	_, err := withClient(ctx, e, func(client *ethclient.Client) (struct{}, error) {
+		return struct{}{}, client.Client().CallContext(ctx, result, method, args...)
+	})
+	return err
+}
+
+// withClient 使用共享客户端执行 fn，调用会经过 Use 注册的中间件
+// 连接失效时重连，配置了多个节点时遇到连接异常或限流等可重试错误会依次切换到其他节点
+func withClient[T any](ctx context.Context, e *EthHelper, fn func(client *ethclient.Client) (T, error)) (T, error) {
+	var (
+		zero    T
+		lastErr error
+	)
+	endpoints := e.readOrder()
+	if len(endpoints) == 1 {
+		// 单节点时重连后再试一次，限制容量避免 append 写入节点池共享的底层数组
+		endpoints = append(endpoints[:1:1], endpoints[0])
+	}
+	for _, ep := range endpoints {
+		res, err := callEndpoint(ctx, e, ep, fn)
+		if err == nil || !IsRetryableError(err) {
+			return res, err
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return zero, lastErr
+}
+
+// callEndpoint 经过中间件在指定节点上执行 fn，并记录节点的延迟和健康状态
+func callEndpoint[T any](ctx context.Context, e *EthHelper, ep *endpoint, fn func(client *ethclient.Client) (T, error)) (T, error) {
+	var zero T
+	client, err := ep.getClient(ctx)
+	if err != nil {
+		ep.record(0, false)
+		return zero, &connectionError{err: err}
+	}
+	start := time.Now()
+	var res T
+	err = e.invoke(ctx, ep.url, func() error {
+		var callErr error
+		res, callErr = fn(client)
+		return callErr
+	})
+	if err != nil && isConnectionError(err) {
+		ep.reset(client)
+		ep.record(0, false)
+		return res, err
+	}
+	ep.record(time.Since(start), true)
+	return res, err
+}
+
+// broadcast 发送写请求，BroadcastAll 模式下同时发送到所有节点，任意一个成功即返回成功
+func (e *EthHelper) broadcast(ctx context.Context, fn func(client *ethclient.Client) error) error {
+	call := func(client *ethclient.Client) (struct{}, error) {
+		return struct{}{}, fn(client)
+	}
+	if e.endpointOptions.BroadcastMode != BroadcastAll {
+		_, err := withClient(ctx, e, call)
+		return err
+	}
+	endpoints := e.pool()
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			_, errs[i] = callEndpoint(ctx, e, ep, call)
+		}(i, ep)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// connectionError 建立连接失败
+type connectionError struct {
+	err error
+}
+
+func (c *connectionError) Error() string { return c.err.Error() }
+func (c *connectionError) Unwrap() error { return c.err }
+
+// isConnectionError 判断错误是否由底层连接失效引起
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var connErr *connectionError
+	if errors.As(err, &connErr) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, rpc.ErrClientQuit) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "broken pipe") ||
+		strings.Contains(msg, "use of closed network connection")
+}
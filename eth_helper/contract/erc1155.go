@@ -2,15 +2,15 @@ package contract
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/shopspring/decimal"
 	"github.com/web3coderecho/web3_helper/eth_helper"
 	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc1155"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
 )
 
 type ERC1155 struct {
@@ -27,26 +27,17 @@ func NewErc1155(eth *eth_helper.EthHelper, address common.Address) *ERC1155 {
 	}
 }
 
-func (erc *ERC1155) GetErc1155(ctx context.Context) (*erc1155.Erc1155, *ethclient.Client, error) {
-	client, err := erc.eth.NewEthClient(ctx)
-	if err != nil {
-		return nil, nil, err
-	}
-	erc1155Contract, err := erc1155.NewErc1155(erc.ContractAddress, client)
-	if err != nil {
-		client.Close()
-		return nil, nil, err
-	}
-	return erc1155Contract, client, nil
+// GetErc1155 返回绑定到 EthHelper 共享连接的合约实例
+func (erc *ERC1155) GetErc1155(ctx context.Context) (*erc1155.Erc1155, error) {
+	return erc1155.NewErc1155(erc.ContractAddress, erc.eth.ContractBackend())
 }
 
 func (erc *ERC1155) BalanceOf(ctx context.Context, account common.Address, id *big.Int) (decimal.Decimal, error) {
-	caller, client, err := erc.GetErc1155(ctx)
-	defer client.Close()
+	caller, err := erc.GetErc1155(ctx)
 	if err != nil {
 		return decimal.Zero, err
 	}
-	balance, err := caller.BalanceOf(nil, account, id)
+	balance, err := caller.BalanceOf(&bind.CallOpts{Context: ctx}, account, id)
 	if err != nil {
 		return decimal.Zero, err
 	}
@@ -54,70 +45,67 @@ func (erc *ERC1155) BalanceOf(ctx context.Context, account common.Address, id *b
 }
 
 func (erc *ERC1155) BalanceOfBatch(ctx context.Context, accounts []common.Address, ids []*big.Int) ([]*big.Int, error) {
-	caller, client, err := erc.GetErc1155(ctx)
-	defer client.Close()
+	caller, err := erc.GetErc1155(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return caller.BalanceOfBatch(nil, accounts, ids)
+	return caller.BalanceOfBatch(&bind.CallOpts{Context: ctx}, accounts, ids)
 }
 
 func (erc *ERC1155) IsApprovedForAll(ctx context.Context, account common.Address, operator common.Address) (bool, error) {
-	caller, client, err := erc.GetErc1155(ctx)
-	defer client.Close()
+	caller, err := erc.GetErc1155(ctx)
 	if err != nil {
 		return false, err
 	}
-	return caller.IsApprovedForAll(nil, account, operator)
+	return caller.IsApprovedForAll(&bind.CallOpts{Context: ctx}, account, operator)
 }
 
-func (erc *ERC1155) SafeTransferFrom(ctx context.Context, from common.Address, to common.Address, id *big.Int, value *big.Int, data []byte, privateKey *ecdsa.PrivateKey) (common.Hash, error) {
+// SafeTransferFrom 把 signer 持有的 value 个 id 转给 to
+func (erc *ERC1155) SafeTransferFrom(ctx context.Context, signer eth_interface.SignerInterface, to common.Address, id *big.Int, value *big.Int, data []byte) (common.Hash, error) {
 	abi, _ := erc1155.Erc1155MetaData.GetAbi()
-	data, err := abi.Pack("safeTransferFrom", to, id, value)
+	input, err := abi.Pack("safeTransferFrom", signer.Address(), to, id, value, data)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return erc.eth.Transaction(ctx, from, privateKey, erc.ContractAddress, decimal.Zero, 0, common.Big0, 0, data)
+	return erc.eth.Transaction(ctx, signer, erc.ContractAddress, decimal.Zero, 0, common.Big0, nil, input)
 }
 
-func (erc *ERC1155) SafeBatchTransferFrom(ctx context.Context, from common.Address, to common.Address, ids []*big.Int, values []*big.Int, data []byte, privateKey *ecdsa.PrivateKey) (common.Hash, error) {
+// SafeBatchTransferFrom 把 signer 持有的多个 id 一次转给 to，ids 与 values 一一对应
+func (erc *ERC1155) SafeBatchTransferFrom(ctx context.Context, signer eth_interface.SignerInterface, to common.Address, ids []*big.Int, values []*big.Int, data []byte) (common.Hash, error) {
 	abi, _ := erc1155.Erc1155MetaData.GetAbi()
-	data, err := abi.Pack("safeBatchTransferFrom", to, ids, values)
+	input, err := abi.Pack("safeBatchTransferFrom", signer.Address(), to, ids, values, data)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return erc.eth.Transaction(ctx, from, privateKey, erc.ContractAddress, decimal.Zero, 0, common.Big0, 0, data)
+	return erc.eth.Transaction(ctx, signer, erc.ContractAddress, decimal.Zero, 0, common.Big0, nil, input)
 }
 
-func (erc *ERC1155) SetApprovalForAll(ctx context.Context, from, operator common.Address, approved bool, privateKey *ecdsa.PrivateKey) (common.Hash, error) {
+func (erc *ERC1155) SetApprovalForAll(ctx context.Context, signer eth_interface.SignerInterface, operator common.Address, approved bool) (common.Hash, error) {
 	abi, _ := erc1155.Erc1155MetaData.GetAbi()
 	data, err := abi.Pack("setApprovalForAll", operator, approved)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return erc.eth.Transaction(ctx, from, privateKey, erc.ContractAddress, decimal.Zero, 0, common.Big0, 0, data)
+	return erc.eth.Transaction(ctx, signer, erc.ContractAddress, decimal.Zero, 0, common.Big0, nil, data)
 }
 
 func (erc *ERC1155) Uri(ctx context.Context, id *big.Int) (string, error) {
-	caller, client, err := erc.GetErc1155(ctx)
-	defer client.Close()
+	caller, err := erc.GetErc1155(ctx)
 	if err != nil {
 		return "", err
 	}
-	return caller.Uri(nil, id)
+	return caller.Uri(&bind.CallOpts{Context: ctx}, id)
 }
 
 func (erc *ERC1155) ParseTransferSingle(log types.Log) (*erc1155.Erc1155TransferSingle, error) {
-	filterer, client, err := erc.GetErc1155(context.Background())
-	defer client.Close()
+	filterer, err := erc.GetErc1155(context.Background())
 	if err != nil {
 		return nil, err
 	}
 	return filterer.ParseTransferSingle(log)
 }
 func (erc *ERC1155) ParseTransferBatch(log types.Log) (*erc1155.Erc1155TransferBatch, error) {
-	filterer, client, err := erc.GetErc1155(context.Background())
-	defer client.Close()
+	filterer, err := erc.GetErc1155(context.Background())
 	if err != nil {
 		return nil, err
 	}
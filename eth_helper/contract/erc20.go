@@ -2,18 +2,33 @@ package contract
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/shopspring/decimal"
 	"github.com/web3coderecho/web3_helper/eth_helper"
 	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+	"github.com/web3coderecho/web3_helper/eth_helper/internal/abiutil"
+	"github.com/web3coderecho/web3_helper/eth_helper/revert"
 	"github.com/web3coderecho/web3_helper/utils"
 )
 
+// ErrAllowanceMethodUnsupported 代币没有 increaseAllowance/decreaseAllowance 方法（如 USDT、OpenZeppelin 5.x）
+var ErrAllowanceMethodUnsupported = errors.New("token does not support increaseAllowance/decreaseAllowance")
+
+// erc20AllowanceABI OpenZeppelin 4.x 等代币提供的原子授权修改方法
+const erc20AllowanceABI = `[
+{"inputs":[{"internalType":"address","name":"spender","type":"address"},{"internalType":"uint256","name":"addedValue","type":"uint256"}],"name":"increaseAllowance","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"internalType":"address","name":"spender","type":"address"},{"internalType":"uint256","name":"subtractedValue","type":"uint256"}],"name":"decreaseAllowance","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}
+]`
+
+var parsedAllowanceABI = abiutil.MustParseABI(erc20AllowanceABI)
+
 type ERC20 struct {
 	ContractAddress common.Address
 	Name            string
@@ -29,29 +44,20 @@ func NewErc20(eth *eth_helper.EthHelper, address common.Address) *ERC20 {
 	}
 }
 
-func (erc *ERC20) GetErc20(ctx context.Context) (*erc20.Erc20, *ethclient.Client, error) {
-	client, err := erc.eth.NewEthClient(ctx)
-	if err != nil {
-		return nil, nil, err
-	}
-	erc20Contract, err := erc20.NewErc20(erc.ContractAddress, client)
-	if err != nil {
-		client.Close()
-		return nil, nil, err
-	}
-	return erc20Contract, client, nil
+// GetErc20 返回绑定到 EthHelper 共享连接的合约实例
+func (erc *ERC20) GetErc20(ctx context.Context) (*erc20.Erc20, error) {
+	return erc20.NewErc20(erc.ContractAddress, erc.eth.ContractBackend())
 }
 
 func (erc *ERC20) GetDecimals(ctx context.Context) (int, error) {
 	if erc.Decimals != 0 {
 		return erc.Decimals, nil
 	}
-	caller, client, err := erc.GetErc20(ctx)
+	caller, err := erc.GetErc20(ctx)
 	if err != nil {
 		return 0, err
 	}
-	defer client.Close()
-	decimals, err := caller.Decimals(nil)
+	decimals, err := caller.Decimals(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return 0, err
 	}
@@ -60,12 +66,11 @@ func (erc *ERC20) GetDecimals(ctx context.Context) (int, error) {
 }
 
 func (erc *ERC20) BalanceOf(ctx context.Context, address common.Address) (decimal.Decimal, error) {
-	caller, client, err := erc.GetErc20(ctx)
+	caller, err := erc.GetErc20(ctx)
 	if err != nil {
 		return decimal.Zero, err
 	}
-	defer client.Close()
-	balance, err := caller.BalanceOf(nil, address)
+	balance, err := caller.BalanceOf(&bind.CallOpts{Context: ctx}, address)
 	if err != nil {
 		return decimal.Zero, err
 	}
@@ -83,12 +88,11 @@ func (erc *ERC20) GetName(ctx context.Context) (string, error) {
 	if erc.Name != "" {
 		return erc.Name, nil
 	}
-	caller, client, err := erc.GetErc20(ctx)
+	caller, err := erc.GetErc20(ctx)
 	if err != nil {
 		return "", err
 	}
-	defer client.Close()
-	name, err := caller.Name(nil)
+	name, err := caller.Name(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return "", err
 	}
@@ -99,12 +103,11 @@ func (erc *ERC20) GetSymbol(ctx context.Context) (string, error) {
 	if erc.Symbol != "" {
 		return erc.Symbol, nil
 	}
-	caller, client, err := erc.GetErc20(ctx)
+	caller, err := erc.GetErc20(ctx)
 	if err != nil {
 		return "", err
 	}
-	defer client.Close()
-	symbol, err := caller.Symbol(nil)
+	symbol, err := caller.Symbol(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return "", err
 	}
@@ -113,12 +116,11 @@ func (erc *ERC20) GetSymbol(ctx context.Context) (string, error) {
 }
 
 func (erc *ERC20) TotalSupply(ctx context.Context) (decimal.Decimal, error) {
-	caller, client, err := erc.GetErc20(ctx)
+	caller, err := erc.GetErc20(ctx)
 	if err != nil {
 		return decimal.Zero, err
 	}
-	defer client.Close()
-	totalSupply, err := caller.TotalSupply(nil)
+	totalSupply, err := caller.TotalSupply(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return decimal.Zero, err
 	}
@@ -129,12 +131,11 @@ func (erc *ERC20) TotalSupply(ctx context.Context) (decimal.Decimal, error) {
 	return utils.FromWeiWithDecimals(totalSupply, decimals), err
 }
 func (erc *ERC20) Allowance(ctx context.Context, owner, spender common.Address) (decimal.Decimal, error) {
-	caller, client, err := erc.GetErc20(ctx)
+	caller, err := erc.GetErc20(ctx)
 	if err != nil {
 		return decimal.Zero, err
 	}
-	defer client.Close()
-	allowance, err := caller.Allowance(nil, owner, spender)
+	allowance, err := caller.Allowance(&bind.CallOpts{Context: ctx}, owner, spender)
 	if err != nil {
 		return decimal.Zero, err
 	}
@@ -145,7 +146,47 @@ func (erc *ERC20) Allowance(ctx context.Context, owner, spender common.Address)
 	return utils.FromWeiWithDecimals(allowance, decimals), err
 }
 
-func (erc *ERC20) Transfer(ctx context.Context, from, to common.Address, amount decimal.Decimal, privateKey *ecdsa.PrivateKey) (common.Hash, error) {
+// Transfer 由 opts.Signer 把代币转给 to
+func (erc *ERC20) Transfer(ctx context.Context, to common.Address, amount decimal.Decimal, opts eth_helper.TxOptions) (common.Hash, error) {
+	if opts.Signer == nil {
+		return common.Hash{}, eth_helper.ErrMissingSigner
+	}
+	balance, err := erc.BalanceOf(ctx, opts.Signer.Address())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if balance.LessThan(amount) {
+		return common.Hash{}, fmt.Errorf("erc20 balance is not enough")
+	}
+	decimals, err := erc.GetDecimals(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	abi, _ := erc20.Erc20MetaData.GetAbi()
+	data, err := abi.Pack("transfer", to, utils.ToWeiWithDecimals(amount, decimals))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return erc.eth.Transact(ctx, erc.ContractAddress, decimal.Zero, data, opts)
+}
+
+// Approve 把 spender 的授权额度直接设置为 amount
+func (erc *ERC20) Approve(ctx context.Context, spender common.Address, amount decimal.Decimal, opts eth_helper.TxOptions) (common.Hash, error) {
+	if opts.Signer == nil {
+		return common.Hash{}, eth_helper.ErrMissingSigner
+	}
+	decimals, err := erc.GetDecimals(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return erc.approve(ctx, spender, utils.ToWeiWithDecimals(amount, decimals), opts)
+}
+
+// TransferFrom 使用 opts.Signer 获得的授权，把 from 的代币转给 to
+func (erc *ERC20) TransferFrom(ctx context.Context, from, to common.Address, amount decimal.Decimal, opts eth_helper.TxOptions) (common.Hash, error) {
+	if opts.Signer == nil {
+		return common.Hash{}, eth_helper.ErrMissingSigner
+	}
 	balance, err := erc.BalanceOf(ctx, from)
 	if err != nil {
 		return common.Hash{}, err
@@ -153,26 +194,158 @@ func (erc *ERC20) Transfer(ctx context.Context, from, to common.Address, amount
 	if balance.LessThan(amount) {
 		return common.Hash{}, fmt.Errorf("erc20 balance is not enough")
 	}
+	allowance, err := erc.Allowance(ctx, from, opts.Signer.Address())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if allowance.LessThan(amount) {
+		return common.Hash{}, fmt.Errorf("erc20 allowance is not enough")
+	}
+	decimals, err := erc.GetDecimals(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
 	abi, _ := erc20.Erc20MetaData.GetAbi()
-	data, err := abi.Pack("transfer", to, utils.ToWeiWithDecimals(amount, erc.Decimals))
+	data, err := abi.Pack("transferFrom", from, to, utils.ToWeiWithDecimals(amount, decimals))
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return erc.eth.Transaction(ctx, from, privateKey, erc.ContractAddress, decimal.Zero, 0, common.Big0, 0, data)
+	return erc.eth.Transact(ctx, erc.ContractAddress, decimal.Zero, data, opts)
+}
+
+// IncreaseAllowance 调用代币的 increaseAllowance 原子地增加授权额度
+// 代币没有该方法时返回 ErrAllowanceMethodUnsupported，可以改用 SafeApprove
+func (erc *ERC20) IncreaseAllowance(ctx context.Context, spender common.Address, amount decimal.Decimal, opts eth_helper.TxOptions) (common.Hash, error) {
+	return erc.changeAllowance(ctx, "increaseAllowance", spender, amount, opts)
+}
+
+// DecreaseAllowance 调用代币的 decreaseAllowance 原子地减少授权额度，额度不足时由代币回滚
+// 代币没有该方法时返回 ErrAllowanceMethodUnsupported，可以改用 SafeApprove
+func (erc *ERC20) DecreaseAllowance(ctx context.Context, spender common.Address, amount decimal.Decimal, opts eth_helper.TxOptions) (common.Hash, error) {
+	return erc.changeAllowance(ctx, "decreaseAllowance", spender, amount, opts)
+}
+
+func (erc *ERC20) changeAllowance(ctx context.Context, method string, spender common.Address, amount decimal.Decimal, opts eth_helper.TxOptions) (common.Hash, error) {
+	if opts.Signer == nil {
+		return common.Hash{}, eth_helper.ErrMissingSigner
+	}
+	decimals, err := erc.GetDecimals(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	data, err := parsedAllowanceABI.Pack(method, spender, utils.ToWeiWithDecimals(amount, decimals))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hash, err := erc.eth.Transact(ctx, erc.ContractAddress, decimal.Zero, data, opts)
+	// 合约没有对应方法时估算 gas 会回滚且没有回滚数据，但暂停、黑名单等 require 也是这样
+	// 只有确认合约代码中没有该方法时才返回 ErrAllowanceMethodUnsupported，否则返回原始错误
+	var revertErr *revert.RevertError
+	if errors.As(err, &revertErr) && revertErr.Kind == revert.KindUnknown && len(revertErr.Data) == 0 {
+		missing, codeErr := erc.missingMethod(ctx, data[:4])
+		if codeErr == nil && missing {
+			return common.Hash{}, fmt.Errorf("%w: %s: %v", ErrAllowanceMethodUnsupported, method, err)
+		}
+	}
+	return hash, err
+}
+
+// 扫描合约代码用到的 EVM 操作码
+const (
+	opPush1        = 0x60
+	opPush32       = 0x7f
+	opCallCode     = 0xf2
+	opDelegateCall = 0xf4
+)
+
+// missingMethod 在最新区块的合约代码中查找方法选择器
+// 代码中含有 DELEGATECALL/CALLCODE 时可能是代理合约，方法可能在实现合约中，无法确认时返回 false
+func (erc *ERC20) missingMethod(ctx context.Context, selector []byte) (bool, error) {
+	code, err := erc.eth.CodeAt(ctx, erc.ContractAddress, nil)
+	if err != nil || len(code) == 0 {
+		return false, err
+	}
+	want := binary.BigEndian.Uint32(selector)
+	for pc := 0; pc < len(code); pc++ {
+		op := code[pc]
+		switch {
+		case op == opDelegateCall || op == opCallCode:
+			return false, nil
+		case op >= opPush1 && op <= opPush32:
+			size := int(op - opPush1 + 1)
+			end := min(pc+1+size, len(code))
+			// solc 用 PUSH4 压入选择器，选择器以 0 开头时会使用更短的 PUSH
+			if size <= 4 && new(big.Int).SetBytes(code[pc+1:end]).Uint64() == uint64(want) {
+				return false, nil
+			}
+			pc = end - 1
+		}
+	}
+	return true, nil
+}
+
+// SafeApprove 设置授权额度，当前额度不为 0 时先授权为 0 并等待上链，兼容 USDT 这类不允许直接修改非 0 授权的代币
+// 返回最后一笔授权交易的哈希，额度没有变化时不发送交易并返回空哈希
+// 两笔交易不是原子的，spender 可能在重置前用掉旧额度，支持 IncreaseAllowance/DecreaseAllowance 的代币应优先使用它们
+func (erc *ERC20) SafeApprove(ctx context.Context, spender common.Address, amount decimal.Decimal, opts eth_helper.TxOptions) (common.Hash, error) {
+	current, value, err := erc.allowanceChange(ctx, spender, amount, opts)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return erc.safeApprove(ctx, spender, current, value, opts)
+}
+
+// allowanceChange 查询当前授权额度，并把 amount 按代币精度换算为最小单位
+func (erc *ERC20) allowanceChange(ctx context.Context, spender common.Address, amount decimal.Decimal, opts eth_helper.TxOptions) (*big.Int, *big.Int, error) {
+	if opts.Signer == nil {
+		return nil, nil, eth_helper.ErrMissingSigner
+	}
+	caller, err := erc.GetErc20(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	current, err := caller.Allowance(&bind.CallOpts{Context: ctx}, opts.Signer.Address(), spender)
+	if err != nil {
+		return nil, nil, err
+	}
+	decimals, err := erc.GetDecimals(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, utils.ToWeiWithDecimals(amount, decimals), nil
+}
+
+func (erc *ERC20) safeApprove(ctx context.Context, spender common.Address, current, value *big.Int, opts eth_helper.TxOptions) (common.Hash, error) {
+	if current.Cmp(value) == 0 {
+		return common.Hash{}, nil
+	}
+	if current.Sign() > 0 && value.Sign() > 0 {
+		hash, err := erc.approve(ctx, spender, common.Big0, opts)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to reset allowance: %w", err)
+		}
+		if _, err = erc.eth.WaitForReceipt(ctx, hash, eth_helper.WaitOptions{Confirmations: 1}); err != nil {
+			return common.Hash{}, fmt.Errorf("failed to reset allowance: %w", err)
+		}
+		if opts.Nonce != nil {
+			next := *opts.Nonce + 1
+			opts.Nonce = &next
+		}
+	}
+	return erc.approve(ctx, spender, value, opts)
 }
 
-func (erc *ERC20) Approve(ctx context.Context, from, spender common.Address, amount decimal.Decimal, privateKey *ecdsa.PrivateKey) (common.Hash, error) {
+func (erc *ERC20) approve(ctx context.Context, spender common.Address, value *big.Int, opts eth_helper.TxOptions) (common.Hash, error) {
 	abi, _ := erc20.Erc20MetaData.GetAbi()
-	data, err := abi.Pack("approve", spender, utils.ToWeiWithDecimals(amount, erc.Decimals))
+	data, err := abi.Pack("approve", spender, value)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return erc.eth.Transaction(ctx, from, privateKey, erc.ContractAddress, decimal.Zero, 0, common.Big0, 0, data)
+	return erc.eth.Transact(ctx, erc.ContractAddress, decimal.Zero, data, opts)
 }
 
 func (erc *ERC20) ParseTransfer(ctx context.Context, log types.Log) (*erc20.Erc20Transfer, error) {
-	filterer, client, err := erc.GetErc20(ctx)
-	defer client.Close()
+	filterer, err := erc.GetErc20(ctx)
 	if err != nil {
 		return nil, err
 	}
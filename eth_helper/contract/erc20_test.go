@@ -0,0 +1,263 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc1155"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc721"
+	"github.com/web3coderecho/web3_helper/eth_helper/revert"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+	"github.com/web3coderecho/web3_helper/eth_helper/signer"
+)
+
+// allowanceToken 在 testToken 的基础上支持 increaseAllowance/decreaseAllowance
+var allowanceToken = common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+
+// pausedToken 代码中有 increaseAllowance，但调用时没有回滚数据，如已暂停的代币
+var pausedToken = common.HexToAddress("0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984")
+
+// proxyToken 是最小代理合约，无法从代码判断实现合约是否有 increaseAllowance
+var proxyToken = common.HexToAddress("0x514910771AF9Ca656af840dff83E8264EcF986CA")
+
+// tokenCodes 各代币在模拟节点中的合约代码
+var tokenCodes = map[common.Address]string{
+	testToken:      "0x608060405263a9059cbb14",
+	allowanceToken: "0x608060405263a9059cbb146339509351146000fd",
+	pausedToken:    "0x608060405263a9059cbb146339509351146000fd",
+	proxyToken:     "0x363d3d373d3d3d363d73bebebebebebebebebebebebebebebebebebebebe5af43d82803e903d91602b57fd5bf3",
+}
+
+// txChain 记录发送的交易，每笔交易都立即在区块 10 中执行成功
+type txChain struct {
+	mu   sync.Mutex
+	sent []*types.Transaction
+}
+
+func (c *txChain) transactions() []*types.Transaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*types.Transaction(nil), c.sent...)
+}
+
+// newERC20Server 模拟 tokenContracts、测试代币和 NFT 合约，只有 ABI 中存在的方法能够估算 gas
+func newERC20Server(t *testing.T, chain *txChain) *eth_helper.EthHelper {
+	t.Helper()
+	contracts := tokenContracts()
+	allowance := contracts[testToken]
+	allowance.abi = mergeABI(allowance.abi, parsedAllowanceABI)
+	contracts[allowanceToken] = allowance
+	contracts[testToken].outputs["balanceOf"] = []interface{}{big.NewInt(5000000)}
+	contracts[pausedToken] = contracts[testToken]
+	contracts[proxyToken] = contracts[testToken]
+	erc721ABI, _ := erc721.Erc721MetaData.GetAbi()
+	contracts[testNFT] = stubContract{abi: *erc721ABI}
+	erc1155ABI, _ := erc1155.Erc1155MetaData.GetAbi()
+	contracts[testMultiToken] = stubContract{abi: *erc1155ABI}
+	header := &types.Header{Number: big.NewInt(10), Difficulty: common.Big0}
+	server := newContractServer(t, contracts, rpctest.Handlers{
+		"eth_estimateGas": func(params []json.RawMessage) (interface{}, error) {
+			args := rpctest.ParseCall(params)
+			if contract, ok := contracts[*args.To]; ok {
+				if _, err := contract.abi.MethodById(args.Calldata()[:4]); err == nil {
+					return "0xea60", nil
+				}
+			}
+			return nil, rpctest.ErrReverted
+		},
+		"eth_getCode": func(params []json.RawMessage) (interface{}, error) {
+			var address common.Address
+			_ = json.Unmarshal(params[0], &address)
+			return tokenCodes[address], nil
+		},
+		"eth_feeHistory":          rpctest.Result(json.RawMessage(`{"oldestBlock":"0x1","baseFeePerGas":["0x3b9aca00","0x3b9aca00"],"gasUsedRatio":[0.5],"reward":[["0x3b9aca00","0x3b9aca00"]]}`)),
+		"eth_getBalance":          rpctest.Result("0xde0b6b3a7640000"),
+		"eth_getTransactionCount": rpctest.Result("0x4"),
+		"eth_blockNumber":         rpctest.Result("0xa"),
+		"eth_getBlockByNumber":    rpctest.Result(header),
+		"eth_sendRawTransaction": func(params []json.RawMessage) (interface{}, error) {
+			var raw hexutil.Bytes
+			_ = json.Unmarshal(params[0], &raw)
+			tx := new(types.Transaction)
+			if err := tx.UnmarshalBinary(raw); err != nil {
+				return nil, err
+			}
+			chain.mu.Lock()
+			chain.sent = append(chain.sent, tx)
+			chain.mu.Unlock()
+			return tx.Hash(), nil
+		},
+		"eth_getTransactionReceipt": func(params []json.RawMessage) (interface{}, error) {
+			var hash common.Hash
+			_ = json.Unmarshal(params[0], &hash)
+			return &types.Receipt{
+				Status:      types.ReceiptStatusSuccessful,
+				TxHash:      hash,
+				BlockHash:   header.Hash(),
+				BlockNumber: header.Number,
+				Logs:        []*types.Log{},
+			}, nil
+		},
+	})
+	eth := eth_helper.NewEthHelper(server.URL)
+	eth.SetTxType(eth_helper.TxTypeLegacy)
+	t.Cleanup(eth.Close)
+	return eth
+}
+
+// unpackCall 解析交易调用的方法和参数
+func unpackCall(t *testing.T, contractABI abi.ABI, tx *types.Transaction) (string, []interface{}) {
+	t.Helper()
+	method, err := contractABI.MethodById(tx.Data()[:4])
+	if err != nil {
+		t.Fatalf("unknown method in transaction: %v", err)
+	}
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		t.Fatalf("failed to unpack %s: %v", method.Name, err)
+	}
+	return method.Name, args
+}
+
+func TestERC20_IncreaseAllowance(t *testing.T) {
+	s, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	spender := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	opts := eth_helper.TxOptions{Signer: s, GasPrice: big.NewInt(1e9)}
+	var chain txChain
+	eth := newERC20Server(t, &chain)
+
+	if _, err := NewErc20(eth, allowanceToken).IncreaseAllowance(context.Background(), spender, decimal.RequireFromString("2.5"), opts); err != nil {
+		t.Fatalf("IncreaseAllowance() error = %v", err)
+	}
+	if _, err := NewErc20(eth, allowanceToken).DecreaseAllowance(context.Background(), spender, decimal.RequireFromString("1"), opts); err != nil {
+		t.Fatalf("DecreaseAllowance() error = %v", err)
+	}
+	sent := chain.transactions()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d transactions, want 2", len(sent))
+	}
+	want := []struct {
+		method string
+		value  int64
+	}{{"increaseAllowance", 2500000}, {"decreaseAllowance", 1000000}}
+	for i, tx := range sent {
+		method, args := unpackCall(t, parsedAllowanceABI, tx)
+		if method != want[i].method || args[0] != spender || args[1].(*big.Int).Int64() != want[i].value {
+			t.Errorf("transaction %d = %s%v, want %s(%s, %d)", i, method, args, want[i].method, spender.Hex(), want[i].value)
+		}
+	}
+
+	// testToken 没有 increaseAllowance，不能退回到非原子的 approve
+	_, err := NewErc20(eth, testToken).IncreaseAllowance(context.Background(), spender, decimal.RequireFromString("2.5"), opts)
+	if !errors.Is(err, ErrAllowanceMethodUnsupported) {
+		t.Errorf("IncreaseAllowance() error = %v, want ErrAllowanceMethodUnsupported", err)
+	}
+	// 代码中有该方法或无法确认时返回原始的回滚错误
+	for _, token := range []common.Address{pausedToken, proxyToken} {
+		_, err = NewErc20(eth, token).IncreaseAllowance(context.Background(), spender, decimal.RequireFromString("2.5"), opts)
+		var revertErr *revert.RevertError
+		if !errors.As(err, &revertErr) || errors.Is(err, ErrAllowanceMethodUnsupported) {
+			t.Errorf("IncreaseAllowance(%s) error = %v, want the original revert error", token.Hex(), err)
+		}
+	}
+	if _, err = NewErc20(eth, testToken).DecreaseAllowance(context.Background(), spender, decimal.NewFromInt(1), eth_helper.TxOptions{}); !errors.Is(err, eth_helper.ErrMissingSigner) {
+		t.Errorf("DecreaseAllowance() error = %v, want ErrMissingSigner", err)
+	}
+	if got := len(chain.transactions()); got != 2 {
+		t.Errorf("sent %d transactions, want 2", got)
+	}
+}
+
+func TestERC20_SafeApprove(t *testing.T) {
+	s, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	spender := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	opts := eth_helper.TxOptions{Signer: s, GasPrice: big.NewInt(1e9)}
+	var chain txChain
+	erc := NewErc20(newERC20Server(t, &chain), testToken)
+	erc20ABI, _ := erc20.Erc20MetaData.GetAbi()
+
+	// 模拟节点中当前授权额度为 7.5，额度不变时不发送交易
+	hash, err := erc.SafeApprove(context.Background(), spender, decimal.RequireFromString("7.5"), opts)
+	if err != nil || hash != (common.Hash{}) || len(chain.transactions()) != 0 {
+		t.Errorf("SafeApprove() with unchanged allowance = %s, %v, want no transaction", hash, err)
+	}
+
+	// 先授权为 0 并等待上链，再授权新额度
+	hash, err = erc.SafeApprove(context.Background(), spender, decimal.RequireFromString("10"), opts)
+	if err != nil {
+		t.Fatalf("SafeApprove() error = %v", err)
+	}
+	sent := chain.transactions()
+	if len(sent) != 2 || hash != sent[1].Hash() {
+		t.Fatalf("SafeApprove() sent %d transactions, returned %s", len(sent), hash)
+	}
+	for i, value := range []int64{0, 10000000} {
+		method, args := unpackCall(t, *erc20ABI, sent[i])
+		if method != "approve" || args[0] != spender || args[1].(*big.Int).Int64() != value {
+			t.Errorf("transaction %d = %s%v, want approve(%s, %d)", i, method, args, spender.Hex(), value)
+		}
+	}
+	if sent[0].Nonce() != 4 || sent[1].Nonce() != 5 {
+		t.Errorf("SafeApprove() nonces = %d, %d, want 4, 5", sent[0].Nonce(), sent[1].Nonce())
+	}
+
+	if _, err = erc.TransferFrom(context.Background(), s.Address(), spender, decimal.NewFromInt(1), eth_helper.TxOptions{}); !errors.Is(err, eth_helper.ErrMissingSigner) {
+		t.Errorf("TransferFrom() error = %v, want ErrMissingSigner", err)
+	}
+}
+
+func TestERC20_TransferApprove(t *testing.T) {
+	s, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	nonce := uint64(9)
+	opts := eth_helper.TxOptions{Signer: s, GasPrice: big.NewInt(1e9), Nonce: &nonce}
+	var chain txChain
+	erc := NewErc20(newERC20Server(t, &chain), testToken)
+	erc20ABI, _ := erc20.Erc20MetaData.GetAbi()
+
+	if _, err := erc.Transfer(context.Background(), to, decimal.RequireFromString("1.5"), opts); err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	if _, err := erc.Approve(context.Background(), to, decimal.RequireFromString("3"), opts); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if _, err := erc.Transfer(context.Background(), to, decimal.NewFromInt(6), opts); err == nil {
+		t.Error("Transfer() with insufficient balance succeeded")
+	}
+	sent := chain.transactions()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d transactions, want 2", len(sent))
+	}
+	want := []struct {
+		method string
+		value  int64
+	}{{"transfer", 1500000}, {"approve", 3000000}}
+	for i, tx := range sent {
+		method, args := unpackCall(t, *erc20ABI, tx)
+		if method != want[i].method || args[0] != to || args[1].(*big.Int).Int64() != want[i].value {
+			t.Errorf("transaction %d = %s%v, want %s(%s, %d)", i, method, args, want[i].method, to.Hex(), want[i].value)
+		}
+		// opts 中的 nonce 和 gas price 会被使用
+		if tx.Nonce() != nonce || tx.GasPrice().Cmp(opts.GasPrice) != 0 {
+			t.Errorf("transaction %d nonce = %d, gas price = %s, want %d, %s", i, tx.Nonce(), tx.GasPrice(), nonce, opts.GasPrice)
+		}
+	}
+
+	if _, err := erc.Transfer(context.Background(), to, decimal.NewFromInt(1), eth_helper.TxOptions{}); !errors.Is(err, eth_helper.ErrMissingSigner) {
+		t.Errorf("Transfer() error = %v, want ErrMissingSigner", err)
+	}
+	if _, err := erc.Approve(context.Background(), to, decimal.NewFromInt(1), eth_helper.TxOptions{}); !errors.Is(err, eth_helper.ErrMissingSigner) {
+		t.Errorf("Approve() error = %v, want ErrMissingSigner", err)
+	}
+}
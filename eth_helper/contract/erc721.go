@@ -2,15 +2,15 @@ package contract
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/shopspring/decimal"
 	"github.com/web3coderecho/web3_helper/eth_helper"
 	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc721"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
 )
 
 type ERC721 struct {
@@ -27,29 +27,20 @@ func NewErc721(eth *eth_helper.EthHelper, address common.Address) *ERC721 {
 	}
 }
 
-func (erc *ERC721) GetErc721(ctx context.Context) (*erc721.Erc721, *ethclient.Client, error) {
-	client, err := erc.eth.NewEthClient(ctx)
-	if err != nil {
-		return nil, nil, err
-	}
-	erc721Contract, err := erc721.NewErc721(erc.ContractAddress, client)
-	if err != nil {
-		client.Close()
-		return nil, nil, err
-	}
-	return erc721Contract, client, nil
+// GetErc721 返回绑定到 EthHelper 共享连接的合约实例
+func (erc *ERC721) GetErc721(ctx context.Context) (*erc721.Erc721, error) {
+	return erc721.NewErc721(erc.ContractAddress, erc.eth.ContractBackend())
 }
 
 func (erc *ERC721) GetName(ctx context.Context) (string, error) {
 	if erc.Name != "" {
 		return erc.Name, nil
 	}
-	caller, client, err := erc.GetErc721(ctx)
-	defer client.Close()
+	caller, err := erc.GetErc721(ctx)
 	if err != nil {
 		return "", err
 	}
-	name, err := caller.Name(nil)
+	name, err := caller.Name(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return "", err
 	}
@@ -61,12 +52,11 @@ func (erc *ERC721) GetSymbol(ctx context.Context) (string, error) {
 	if erc.Symbol != "" {
 		return erc.Symbol, nil
 	}
-	caller, client, err := erc.GetErc721(ctx)
-	defer client.Close()
+	caller, err := erc.GetErc721(ctx)
 	if err != nil {
 		return "", err
 	}
-	symbol, err := caller.Symbol(nil)
+	symbol, err := caller.Symbol(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return "", err
 	}
@@ -75,75 +65,79 @@ func (erc *ERC721) GetSymbol(ctx context.Context) (string, error) {
 }
 
 func (erc *ERC721) OwnerOf(ctx context.Context, tokenId *big.Int) (common.Address, error) {
-	caller, client, err := erc.GetErc721(ctx)
-	defer client.Close()
+	caller, err := erc.GetErc721(ctx)
 	if err != nil {
 		return common.Address{}, err
 	}
-	return caller.OwnerOf(nil, tokenId)
+	return caller.OwnerOf(&bind.CallOpts{Context: ctx}, tokenId)
 }
 
 func (erc *ERC721) BalanceOf(ctx context.Context, owner common.Address) (int64, error) {
-	caller, client, err := erc.GetErc721(ctx)
-	defer client.Close()
+	caller, err := erc.GetErc721(ctx)
 	if err != nil {
 		return 0, err
 	}
-	balance, err := caller.BalanceOf(nil, owner)
+	balance, err := caller.BalanceOf(&bind.CallOpts{Context: ctx}, owner)
 	if err != nil {
 		return 0, err
 	}
 	return balance.Int64(), nil
 }
 
-func (erc *ERC721) Approve(ctx context.Context, from common.Address, to common.Address, tokenId *big.Int, privateKey *ecdsa.PrivateKey) (common.Hash, error) {
+func (erc *ERC721) Approve(ctx context.Context, signer eth_interface.SignerInterface, to common.Address, tokenId *big.Int) (common.Hash, error) {
 	abi, _ := erc721.Erc721MetaData.GetAbi()
 	data, err := abi.Pack("approve", to, tokenId)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return erc.eth.Transaction(ctx, from, privateKey, erc.ContractAddress, decimal.Zero, 0, common.Big0, 0, data)
+	return erc.eth.Transaction(ctx, signer, erc.ContractAddress, decimal.Zero, 0, common.Big0, nil, data)
 }
 
-func (erc *ERC721) SafeTransferFrom(ctx context.Context, from common.Address, to common.Address, tokenId *big.Int, data []byte, privateKey *ecdsa.PrivateKey) (common.Hash, error) {
+// SafeTransferFrom 把 signer 持有的 tokenId 转给 to，data 为空时调用不带 data 的重载
+func (erc *ERC721) SafeTransferFrom(ctx context.Context, signer eth_interface.SignerInterface, to common.Address, tokenId *big.Int, data []byte) (common.Hash, error) {
 	abi, _ := erc721.Erc721MetaData.GetAbi()
-	data, err := abi.Pack("safeTransferFrom", from, to, tokenId, "")
+	var input []byte
+	var err error
+	if len(data) == 0 {
+		input, err = abi.Pack("safeTransferFrom", signer.Address(), to, tokenId)
+	} else {
+		input, err = abi.Pack("safeTransferFrom0", signer.Address(), to, tokenId, data)
+	}
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return erc.eth.Transaction(ctx, from, privateKey, erc.ContractAddress, decimal.Zero, 0, common.Big0, 0, data)
+	return erc.eth.Transaction(ctx, signer, erc.ContractAddress, decimal.Zero, 0, common.Big0, nil, input)
 }
 
-func (erc *ERC721) SetApprovalForAll(ctx context.Context, from common.Address, operator common.Address, approved bool, privateKey *ecdsa.PrivateKey) (common.Hash, error) {
+func (erc *ERC721) SetApprovalForAll(ctx context.Context, signer eth_interface.SignerInterface, operator common.Address, approved bool) (common.Hash, error) {
 	abi, _ := erc721.Erc721MetaData.GetAbi()
 	data, err := abi.Pack("setApprovalForAll", operator, approved)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return erc.eth.Transaction(ctx, from, privateKey, erc.ContractAddress, decimal.Zero, 0, common.Big0, 0, data)
+	return erc.eth.Transaction(ctx, signer, erc.ContractAddress, decimal.Zero, 0, common.Big0, nil, data)
 }
 
 func (erc *ERC721) IsApprovedForAll(ctx context.Context, owner common.Address, operator common.Address) (bool, error) {
-	caller, client, err := erc.GetErc721(ctx)
-	defer client.Close()
+	caller, err := erc.GetErc721(ctx)
 	if err != nil {
 		return false, err
 	}
-	return caller.IsApprovedForAll(nil, owner, operator)
+	return caller.IsApprovedForAll(&bind.CallOpts{Context: ctx}, owner, operator)
 }
 
-func (erc *ERC721) TransferFrom(ctx context.Context, from common.Address, to common.Address, tokenId *big.Int, privateKey *ecdsa.PrivateKey) (common.Hash, error) {
+// TransferFrom 把 signer 持有的 tokenId 转给 to，不检查 to 能否接收 NFT
+func (erc *ERC721) TransferFrom(ctx context.Context, signer eth_interface.SignerInterface, to common.Address, tokenId *big.Int) (common.Hash, error) {
 	abi, _ := erc721.Erc721MetaData.GetAbi()
-	data, err := abi.Pack("transferFrom", from, to, tokenId, "")
+	data, err := abi.Pack("transferFrom", signer.Address(), to, tokenId)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return erc.eth.Transaction(ctx, from, privateKey, erc.ContractAddress, decimal.Zero, 0, common.Big0, 0, data)
+	return erc.eth.Transaction(ctx, signer, erc.ContractAddress, decimal.Zero, 0, common.Big0, nil, data)
 }
 
 func (erc *ERC721) ParseTransfer(ctx context.Context, log types.Log) (*erc721.Erc721Transfer, error) {
-	filterer, client, err := erc.GetErc721(ctx)
-	defer client.Close()
+	filterer, err := erc.GetErc721(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,77 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc1155"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc721"
+	"github.com/web3coderecho/web3_helper/eth_helper/signer"
+)
+
+var (
+	testNFT        = common.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D")
+	testMultiToken = common.HexToAddress("0x76BE3b62873462d2142405439777e971754E8E77")
+)
+
+func TestERC721_Transfers(t *testing.T) {
+	s, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	tokenId := big.NewInt(42)
+	var chain txChain
+	eth := newERC20Server(t, &chain)
+	nft := NewErc721(eth, testNFT)
+
+	if _, err := nft.TransferFrom(context.Background(), s, to, tokenId); err != nil {
+		t.Fatalf("TransferFrom() error = %v", err)
+	}
+	if _, err := nft.SafeTransferFrom(context.Background(), s, to, tokenId, nil); err != nil {
+		t.Fatalf("SafeTransferFrom() error = %v", err)
+	}
+	if _, err := nft.SafeTransferFrom(context.Background(), s, to, tokenId, []byte("memo")); err != nil {
+		t.Fatalf("SafeTransferFrom() with data error = %v", err)
+	}
+	sent := chain.transactions()
+	if len(sent) != 3 {
+		t.Fatalf("sent %d transactions, want 3", len(sent))
+	}
+	// from 总是 signer 的地址
+	erc721ABI, _ := erc721.Erc721MetaData.GetAbi()
+	for i, name := range []string{"transferFrom", "safeTransferFrom", "safeTransferFrom0"} {
+		method, args := unpackCall(t, *erc721ABI, sent[i])
+		if method != name || args[0] != s.Address() || args[1] != to || args[2].(*big.Int).Cmp(tokenId) != 0 {
+			t.Errorf("transaction %d = %s%v, want %s(%s, %s, %s, ...)", i, method, args, name, s.Address().Hex(), to.Hex(), tokenId)
+		}
+	}
+	if _, args := unpackCall(t, *erc721ABI, sent[2]); !bytes.Equal(args[3].([]byte), []byte("memo")) {
+		t.Errorf("safeTransferFrom data = %q, want %q", args[3], "memo")
+	}
+}
+
+func TestERC1155_Transfers(t *testing.T) {
+	s, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	var chain txChain
+	token := NewErc1155(newERC20Server(t, &chain), testMultiToken)
+
+	if _, err := token.SafeTransferFrom(context.Background(), s, to, big.NewInt(1), big.NewInt(5), nil); err != nil {
+		t.Fatalf("SafeTransferFrom() error = %v", err)
+	}
+	if _, err := token.SafeBatchTransferFrom(context.Background(), s, to, []*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(5), big.NewInt(6)}, []byte{1}); err != nil {
+		t.Fatalf("SafeBatchTransferFrom() error = %v", err)
+	}
+	sent := chain.transactions()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d transactions, want 2", len(sent))
+	}
+	erc1155ABI, _ := erc1155.Erc1155MetaData.GetAbi()
+	for i, name := range []string{"safeTransferFrom", "safeBatchTransferFrom"} {
+		method, args := unpackCall(t, *erc1155ABI, sent[i])
+		if method != name || args[0] != s.Address() || args[1] != to {
+			t.Errorf("transaction %d = %s%v, want %s(%s, %s, ...)", i, method, args, name, s.Address().Hex(), to.Hex())
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+)
+
+var (
+	// ErrUnknownMethod ABI 中没有该方法
+	ErrUnknownMethod = errors.New("method not found in contract ABI")
+	// ErrUnknownEvent ABI 中没有该事件或日志不属于该事件
+	ErrUnknownEvent = errors.New("event not found in contract ABI")
+)
+
+// Generic 运行时根据 JSON ABI 调用任意合约，不需要 abigen 生成绑定
+type Generic struct {
+	ContractAddress common.Address
+	ABI             abi.ABI
+	eth             *eth_helper.EthHelper
+}
+
+// Event 解析后的事件日志
+type Event struct {
+	Name   string                 // 事件名称
+	Values map[string]interface{} // 事件参数，包含 indexed 参数
+	Log    types.Log              // 原始日志
+}
+
+// NewGeneric 解析 JSON ABI 并创建合约客户端，ABI 中的自定义错误会注册到 eth 的回滚解析器
+func NewGeneric(eth *eth_helper.EthHelper, contractAddress common.Address, abiJSON string) (*Generic, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %v", err)
+	}
+	eth.RevertDecoder().Register(&parsed)
+	return &Generic{
+		ContractAddress: contractAddress,
+		ABI:             parsed,
+		eth:             eth,
+	}, nil
+}
+
+// Pack 编码方法调用数据
+func (g *Generic) Pack(method string, args ...interface{}) ([]byte, error) {
+	if _, ok := g.ABI.Methods[method]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMethod, method)
+	}
+	data, err := g.ABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %v", method, err)
+	}
+	return data, nil
+}
+
+// Call 在最新区块调用只读方法，按 ABI 顺序返回解码后的输出
+func (g *Generic) Call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	return g.CallAt(ctx, nil, method, args...)
+}
+
+// CallAt 在指定区块调用只读方法，blockNumber 为 nil 表示最新区块
+func (g *Generic) CallAt(ctx context.Context, blockNumber *big.Int, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := g.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := g.eth.CallContract(ctx, ethereum.CallMsg{To: &g.ContractAddress, Data: data}, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	values, err := g.ABI.Unpack(method, output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s: %v", method, err)
+	}
+	return values, nil
+}
+
+// Transact 由 signer 发送调用 method 的交易，gas 和 nonce 使用默认值
+func (g *Generic) Transact(ctx context.Context, signer eth_interface.SignerInterface, method string, args ...interface{}) (common.Hash, error) {
+	data, err := g.Pack(method, args...)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return g.eth.Transaction(ctx, signer, g.ContractAddress, decimal.Zero, 0, common.Big0, nil, data)
+}
+
+// TransactWithOptions 按 opts 发送调用 method 的交易，amount 为附带的 ETH 数量，用于 payable 方法
+func (g *Generic) TransactWithOptions(ctx context.Context, opts eth_helper.TxOptions, amount decimal.Decimal, method string, args ...interface{}) (common.Hash, error) {
+	data, err := g.Pack(method, args...)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return g.eth.Transact(ctx, g.ContractAddress, amount, data, opts)
+}
+
+// DecodeEvent 按事件名称解析日志，返回的参数包含 indexed 参数
+// indexed 的动态类型（string、bytes、数组）只能得到其哈希
+func (g *Generic) DecodeEvent(name string, log types.Log) (map[string]interface{}, error) {
+	event, ok := g.ABI.Events[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, name)
+	}
+	topics := log.Topics
+	if !event.Anonymous {
+		if len(topics) == 0 || topics[0] != event.ID {
+			return nil, fmt.Errorf("%w: log is not %s", ErrUnknownEvent, name)
+		}
+		topics = topics[1:]
+	}
+	values := make(map[string]interface{})
+	if len(log.Data) > 0 {
+		if err := event.Inputs.NonIndexed().UnpackIntoMap(values, log.Data); err != nil {
+			return nil, fmt.Errorf("failed to unpack %s data: %v", name, err)
+		}
+	}
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, topics); err != nil {
+		return nil, fmt.Errorf("failed to parse %s topics: %v", name, err)
+	}
+	return values, nil
+}
+
+// ParseLog 根据 topic0 识别事件并解析，不支持匿名事件
+func (g *Generic) ParseLog(log types.Log) (*Event, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("%w: log has no topics", ErrUnknownEvent)
+	}
+	event, err := g.ABI.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, log.Topics[0].Hex())
+	}
+	values, err := g.DecodeEvent(event.Name, log)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{Name: event.Name, Values: values, Log: log}, nil
+}
+
+// FilterEvents 查询区块范围内合约发出的 name 事件并解析，toBlock 为 nil 表示最新区块
+// query 依次对应事件的 indexed 参数，用于按参数值过滤，nil 表示不限制
+func (g *Generic) FilterEvents(ctx context.Context, name string, fromBlock, toBlock *big.Int, query ...[]interface{}) ([]*Event, error) {
+	event, ok := g.ABI.Events[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, name)
+	}
+	topics, err := abi.MakeTopics(query...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build topics: %v", err)
+	}
+	if !event.Anonymous {
+		topics = append([][]common.Hash{{event.ID}}, topics...)
+	}
+	logs, err := g.eth.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []common.Address{g.ContractAddress},
+		Topics:    topics,
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*Event, 0, len(logs))
+	for _, log := range logs {
+		values, err := g.DecodeEvent(name, log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &Event{Name: name, Values: values, Log: log})
+	}
+	return events, nil
+}
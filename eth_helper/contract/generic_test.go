@@ -0,0 +1,74 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+)
+
+func TestGeneric_Call(t *testing.T) {
+	server := newTokenServer(t)
+	eth := eth_helper.NewEthHelper(server.URL)
+	defer eth.Close()
+	token, err := NewGeneric(eth, testToken, erc20.Erc20MetaData.ABI)
+	if err != nil {
+		t.Fatalf("NewGeneric() error = %v", err)
+	}
+
+	values, err := token.Call(context.Background(), "name")
+	if err != nil || len(values) != 1 || values[0] != "USD Coin" {
+		t.Errorf("Call(name) = %v, %v, want [USD Coin]", values, err)
+	}
+	values, err = token.Call(context.Background(), "decimals")
+	if err != nil || len(values) != 1 || values[0] != uint8(6) {
+		t.Errorf("Call(decimals) = %v, %v, want [6]", values, err)
+	}
+	if _, err := token.Call(context.Background(), "mint"); !errors.Is(err, ErrUnknownMethod) {
+		t.Errorf("Call(mint) error = %v, want ErrUnknownMethod", err)
+	}
+	if _, err := token.Call(context.Background(), "balanceOf"); err == nil {
+		t.Errorf("Call(balanceOf) without arguments error = nil, want error")
+	}
+}
+
+func TestGeneric_DecodeEvent(t *testing.T) {
+	token, err := NewGeneric(eth_helper.NewEthHelper("http://127.0.0.1:0"), testToken, erc20.Erc20MetaData.ABI)
+	if err != nil {
+		t.Fatalf("NewGeneric() error = %v", err)
+	}
+	from := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	to := common.HexToAddress("0xE837C72A310F201AD2E3CA4E44C1DD0D41F4EC8B")
+	data, _ := token.ABI.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(12500000))
+	log := types.Log{
+		Address: testToken,
+		Topics: []common.Hash{
+			token.ABI.Events["Transfer"].ID,
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data: data,
+	}
+
+	event, err := token.ParseLog(log)
+	if err != nil {
+		t.Fatalf("ParseLog() error = %v", err)
+	}
+	if event.Name != "Transfer" {
+		t.Errorf("ParseLog() name = %s, want Transfer", event.Name)
+	}
+	if event.Values["from"] != from || event.Values["to"] != to || event.Values["value"].(*big.Int).Int64() != 12500000 {
+		t.Errorf("ParseLog() values = %v", event.Values)
+	}
+	if _, err := token.DecodeEvent("Approval", log); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("DecodeEvent(Approval) error = %v, want ErrUnknownEvent", err)
+	}
+	if _, err := token.DecodeEvent("Deposit", log); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("DecodeEvent(Deposit) error = %v, want ErrUnknownEvent", err)
+	}
+}
@@ -0,0 +1,192 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+	"github.com/web3coderecho/web3_helper/eth_helper/internal/abiutil"
+	"github.com/web3coderecho/web3_helper/eth_helper/sign"
+	"github.com/web3coderecho/web3_helper/utils"
+)
+
+// ErrDomainSeparatorMismatch 本地计算的 EIP-712 domain separator 与合约返回的不一致，通常是 version 不同
+var ErrDomainSeparatorMismatch = errors.New("permit domain separator does not match the token")
+
+const erc20PermitABI = `[
+{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"nonces","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"DOMAIN_SEPARATOR","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"version","outputs":[{"internalType":"string","name":"","type":"string"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"internalType":"address","name":"owner","type":"address"},{"internalType":"address","name":"spender","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"uint256","name":"deadline","type":"uint256"},{"internalType":"uint8","name":"v","type":"uint8"},{"internalType":"bytes32","name":"r","type":"bytes32"},{"internalType":"bytes32","name":"s","type":"bytes32"}],"name":"permit","outputs":[],"stateMutability":"nonpayable","type":"function"}
+]`
+
+var parsedPermitABI = abiutil.MustParseABI(erc20PermitABI)
+
+// permitTypes EIP-2612 Permit 结构
+var permitTypes = apitypes.Types{
+	"Permit": {
+		{Name: "owner", Type: "address"},
+		{Name: "spender", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	},
+}
+
+// PermitSignature 签名后的 EIP-2612 Permit，可以由任意地址提交
+type PermitSignature struct {
+	Owner     common.Address
+	Spender   common.Address
+	Value     *big.Int
+	Nonce     *big.Int
+	Deadline  *big.Int
+	V         uint8
+	R         [32]byte
+	S         [32]byte
+	Signature []byte // 65 字节 [R || S || V] 签名
+}
+
+// Calldata 编码 permit(owner, spender, value, deadline, v, r, s) 调用
+func (p *PermitSignature) Calldata() ([]byte, error) {
+	return parsedPermitABI.Pack("permit", p.Owner, p.Spender, p.Value, p.Deadline, p.V, p.R, p.S)
+}
+
+// Nonces 查询 owner 当前的 permit nonce
+func (erc *ERC20) Nonces(ctx context.Context, owner common.Address) (*big.Int, error) {
+	values, err := erc.call(ctx, parsedPermitABI, "nonces", owner)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// DomainSeparator 查询合约的 EIP-712 domain separator
+func (erc *ERC20) DomainSeparator(ctx context.Context) (common.Hash, error) {
+	values, err := erc.call(ctx, parsedPermitABI, "DOMAIN_SEPARATOR")
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return values[0].([32]byte), nil
+}
+
+// Permit 签名 EIP-2612 Permit，授权 spender 使用 signer 的 amount 数量代币，deadline 之后失效
+// domain 使用代币的 name、version（没有 version() 时为 "1"）、当前 chainId 和合约地址，并与合约的 DOMAIN_SEPARATOR 校验
+func (erc *ERC20) Permit(ctx context.Context, signer eth_interface.SignerInterface, spender common.Address, amount decimal.Decimal, deadline time.Time) (*PermitSignature, error) {
+	decimals, err := erc.GetDecimals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := erc.permitDomain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owner := signer.Address()
+	nonce, err := erc.Nonces(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permit nonce: %v", err)
+	}
+	permit := &PermitSignature{
+		Owner:    owner,
+		Spender:  spender,
+		Value:    utils.ToWeiWithDecimals(amount, decimals),
+		Nonce:    nonce,
+		Deadline: big.NewInt(deadline.Unix()),
+	}
+	typedData, err := sign.NewTypedData(domain, permitTypes, "Permit", map[string]interface{}{
+		"owner":    owner.Hex(),
+		"spender":  spender.Hex(),
+		"value":    permit.Value,
+		"nonce":    permit.Nonce,
+		"deadline": permit.Deadline,
+	})
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.SignTypedData(ctx, typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign permit: %v", err)
+	}
+	permit.Signature = signature
+	copy(permit.R[:], signature[:32])
+	copy(permit.S[:], signature[32:64])
+	permit.V = signature[64]
+	return permit, nil
+}
+
+// SubmitPermit 由 relayer 发送 permit 交易并支付手续费
+func (erc *ERC20) SubmitPermit(ctx context.Context, relayer eth_interface.SignerInterface, permit *PermitSignature) (common.Hash, error) {
+	data, err := permit.Calldata()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return erc.eth.Transaction(ctx, relayer, erc.ContractAddress, decimal.Zero, 0, common.Big0, nil, data)
+}
+
+// permitDomain 构建代币的 EIP-712 domain 并与合约的 DOMAIN_SEPARATOR 比对
+func (erc *ERC20) permitDomain(ctx context.Context) (apitypes.TypedDataDomain, error) {
+	name, err := erc.GetName(ctx)
+	if err != nil {
+		return apitypes.TypedDataDomain{}, err
+	}
+	version := "1"
+	if values, err := erc.call(ctx, parsedPermitABI, "version"); err == nil {
+		version = values[0].(string)
+	}
+	chainId, err := erc.eth.GetChainId(ctx)
+	if err != nil {
+		return apitypes.TypedDataDomain{}, err
+	}
+	domain := apitypes.TypedDataDomain{
+		Name:              name,
+		Version:           version,
+		ChainId:           (*math.HexOrDecimal256)(chainId),
+		VerifyingContract: erc.ContractAddress.Hex(),
+	}
+	expected, err := erc.DomainSeparator(ctx)
+	if err != nil {
+		return apitypes.TypedDataDomain{}, fmt.Errorf("failed to get DOMAIN_SEPARATOR: %v", err)
+	}
+	actual, err := domainSeparator(domain)
+	if err != nil {
+		return apitypes.TypedDataDomain{}, err
+	}
+	if actual != expected {
+		return apitypes.TypedDataDomain{}, ErrDomainSeparatorMismatch
+	}
+	return domain, nil
+}
+
+// call 使用 ABI 调用代币合约的只读方法
+func (erc *ERC20) call(ctx context.Context, contractABI abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := erc.eth.CallContract(ctx, ethereum.CallMsg{To: &erc.ContractAddress, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return contractABI.Unpack(method, output)
+}
+
+// domainSeparator 计算 EIP-712 domain separator
+func domainSeparator(domain apitypes.TypedDataDomain) (common.Hash, error) {
+	typedData, err := sign.NewTypedData(domain, nil, "EIP712Domain", map[string]interface{}{})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hash, err := typedData.HashStruct("EIP712Domain", domain.Map())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash domain: %v", err)
+	}
+	return common.BytesToHash(hash), nil
+}
@@ -0,0 +1,177 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+	"github.com/web3coderecho/web3_helper/eth_helper/internal/abiutil"
+	"github.com/web3coderecho/web3_helper/eth_helper/sign"
+)
+
+// Permit2Address Uniswap Permit2 在各条链上的部署地址
+var Permit2Address = common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+
+const permit2ABI = `[{"inputs":[{"internalType":"address","name":"","type":"address"},{"internalType":"address","name":"","type":"address"},{"internalType":"address","name":"","type":"address"}],"name":"allowance","outputs":[{"internalType":"uint160","name":"amount","type":"uint160"},{"internalType":"uint48","name":"expiration","type":"uint48"},{"internalType":"uint48","name":"nonce","type":"uint48"}],"stateMutability":"view","type":"function"}]`
+
+var parsedPermit2ABI = abiutil.MustParseABI(permit2ABI)
+
+var (
+	// permitSingleTypes AllowanceTransfer 的 PermitSingle 结构
+	permitSingleTypes = apitypes.Types{
+		"PermitSingle": {
+			{Name: "details", Type: "PermitDetails"},
+			{Name: "spender", Type: "address"},
+			{Name: "sigDeadline", Type: "uint256"},
+		},
+		"PermitDetails": {
+			{Name: "token", Type: "address"},
+			{Name: "amount", Type: "uint160"},
+			{Name: "expiration", Type: "uint48"},
+			{Name: "nonce", Type: "uint48"},
+		},
+	}
+	// permitTransferFromTypes SignatureTransfer 的 PermitTransferFrom 结构
+	permitTransferFromTypes = apitypes.Types{
+		"PermitTransferFrom": {
+			{Name: "permitted", Type: "TokenPermissions"},
+			{Name: "spender", Type: "address"},
+			{Name: "nonce", Type: "uint256"},
+			{Name: "deadline", Type: "uint256"},
+		},
+		"TokenPermissions": {
+			{Name: "token", Type: "address"},
+			{Name: "amount", Type: "uint256"},
+		},
+	}
+)
+
+// PermitDetails Permit2 PermitSingle 中的授权详情
+type PermitDetails struct {
+	Token      common.Address
+	Amount     *big.Int // uint160
+	Expiration uint64   // 授权失效时间，uint48 秒级时间戳
+	Nonce      uint64   // uint48
+}
+
+// PermitSingle Permit2 AllowanceTransfer 授权
+type PermitSingle struct {
+	Details     PermitDetails
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// TokenPermissions Permit2 SignatureTransfer 允许转出的代币和数量
+type TokenPermissions struct {
+	Token  common.Address
+	Amount *big.Int
+}
+
+// PermitTransferFrom Permit2 SignatureTransfer 一次性转账授权，Spender 是调用 permitTransferFrom 的地址
+type PermitTransferFrom struct {
+	Permitted TokenPermissions
+	Spender   common.Address
+	Nonce     *big.Int // 无序 nonce，由调用方保证不重复
+	Deadline  *big.Int
+}
+
+// Permit2 Uniswap Permit2 签名工具
+type Permit2 struct {
+	Address common.Address
+	eth     *eth_helper.EthHelper
+}
+
+// NewPermit2 使用标准 Permit2 地址创建 Permit2
+func NewPermit2(eth *eth_helper.EthHelper) *Permit2 {
+	return &Permit2{Address: Permit2Address, eth: eth}
+}
+
+// Allowance 查询 owner 通过 Permit2 授权给 spender 的额度、失效时间和当前 nonce
+func (p *Permit2) Allowance(ctx context.Context, owner, token, spender common.Address) (amount *big.Int, expiration uint64, nonce uint64, err error) {
+	data, err := parsedPermit2ABI.Pack("allowance", owner, token, spender)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	output, err := p.eth.CallContract(ctx, ethereum.CallMsg{To: &p.Address, Data: data}, nil)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	values, err := parsedPermit2ABI.Unpack("allowance", output)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return values[0].(*big.Int), values[1].(*big.Int).Uint64(), values[2].(*big.Int).Uint64(), nil
+}
+
+// SignPermitSingle 读取当前 nonce 后签名 PermitSingle，amount 为代币最小单位
+func (p *Permit2) SignPermitSingle(
+	ctx context.Context,
+	signer eth_interface.SignerInterface,
+	token, spender common.Address,
+	amount *big.Int,
+	expiration, sigDeadline time.Time,
+) (*PermitSingle, []byte, error) {
+	_, _, nonce, err := p.Allowance(ctx, signer.Address(), token, spender)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get permit2 nonce: %v", err)
+	}
+	permit := &PermitSingle{
+		Details: PermitDetails{
+			Token:      token,
+			Amount:     amount,
+			Expiration: uint64(expiration.Unix()),
+			Nonce:      nonce,
+		},
+		Spender:     spender,
+		SigDeadline: big.NewInt(sigDeadline.Unix()),
+	}
+	signature, err := p.sign(ctx, signer, "PermitSingle", permit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return permit, signature, nil
+}
+
+// SignPermitTransferFrom 签名 PermitTransferFrom
+func (p *Permit2) SignPermitTransferFrom(ctx context.Context, signer eth_interface.SignerInterface, permit PermitTransferFrom) ([]byte, error) {
+	return p.sign(ctx, signer, "PermitTransferFrom", permit)
+}
+
+// TypedData 构建 Permit2 domain 下的 EIP-712 数据，message 为 PermitSingle 或 PermitTransferFrom
+func (p *Permit2) TypedData(ctx context.Context, message interface{}) (apitypes.TypedData, error) {
+	chainId, err := p.eth.GetChainId(ctx)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+	domain := apitypes.TypedDataDomain{
+		Name:              "Permit2",
+		ChainId:           (*math.HexOrDecimal256)(chainId),
+		VerifyingContract: p.Address.Hex(),
+	}
+	switch message.(type) {
+	case PermitSingle, *PermitSingle:
+		return sign.NewTypedData(domain, permitSingleTypes, "PermitSingle", message)
+	case PermitTransferFrom, *PermitTransferFrom:
+		return sign.NewTypedData(domain, permitTransferFromTypes, "PermitTransferFrom", message)
+	}
+	return apitypes.TypedData{}, fmt.Errorf("unsupported permit2 message %T", message)
+}
+
+func (p *Permit2) sign(ctx context.Context, signer eth_interface.SignerInterface, primaryType string, message interface{}) ([]byte, error) {
+	typedData, err := p.TypedData(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.SignTypedData(ctx, typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign %s: %v", primaryType, err)
+	}
+	return signature, nil
+}
@@ -0,0 +1,183 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+	"github.com/web3coderecho/web3_helper/eth_helper/sign"
+	"github.com/web3coderecho/web3_helper/eth_helper/signer"
+)
+
+var testToken = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+// tokenDomainSeparator 按 EIP-712 规范独立计算代币的 domain separator
+func tokenDomainSeparator(name, version string, chainId int64, contract common.Address) []byte {
+	return crypto.Keccak256(
+		crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)")),
+		crypto.Keccak256([]byte(name)),
+		crypto.Keccak256([]byte(version)),
+		common.LeftPadBytes(big.NewInt(chainId).Bytes(), 32),
+		common.LeftPadBytes(contract.Bytes(), 32),
+	)
+}
+
+// stubContract 模拟节点中的合约，outputs 为方法名到返回值的映射，没有返回值的方法调用会回滚
+type stubContract struct {
+	abi     abi.ABI
+	outputs map[string][]interface{}
+}
+
+// mergeABI 合并多个 ABI 的方法，模拟实现了多个接口的合约
+func mergeABI(abis ...abi.ABI) abi.ABI {
+	merged := abi.ABI{Methods: make(map[string]abi.Method)}
+	for _, item := range abis {
+		for name, method := range item.Methods {
+			merged.Methods[name] = method
+		}
+	}
+	return merged
+}
+
+// newContractServer 模拟链 ID 为 1 的节点，eth_call 按合约地址和函数选择器返回 contracts 中的结果
+// handlers 用于补充或覆盖其他方法
+func newContractServer(t *testing.T, contracts map[common.Address]stubContract, handlers rpctest.Handlers) *httptest.Server {
+	t.Helper()
+	all := rpctest.Handlers{
+		"eth_chainId": rpctest.Result("0x1"),
+		"eth_call": func(params []json.RawMessage) (interface{}, error) {
+			args := rpctest.ParseCall(params)
+			input := args.Calldata()
+			if args.To == nil || len(input) < 4 {
+				return nil, rpctest.ErrReverted
+			}
+			contract, ok := contracts[*args.To]
+			if !ok {
+				return nil, rpctest.ErrReverted
+			}
+			method, err := contract.abi.MethodById(input[:4])
+			if err != nil {
+				return nil, rpctest.ErrReverted
+			}
+			output, ok := contract.outputs[method.Name]
+			if !ok {
+				return nil, rpctest.ErrReverted
+			}
+			packed, err := method.Outputs.Pack(output...)
+			if err != nil {
+				return nil, err
+			}
+			return hexutil.Bytes(packed), nil
+		},
+	}
+	for method, handler := range handlers {
+		all[method] = handler
+	}
+	return rpctest.NewServer(t, all)
+}
+
+// tokenContracts testToken 是 version 为 2、精度为 6 的 permit 代币，授权额度为 7.5，Permit2 的 nonce 为 5
+func tokenContracts() map[common.Address]stubContract {
+	erc20ABI, _ := erc20.Erc20MetaData.GetAbi()
+	return map[common.Address]stubContract{
+		testToken: {
+			abi: mergeABI(*erc20ABI, parsedPermitABI),
+			outputs: map[string][]interface{}{
+				"name":             {"USD Coin"},
+				"decimals":         {uint8(6)},
+				"allowance":        {big.NewInt(7500000)},
+				"version":          {"2"},
+				"nonces":           {big.NewInt(3)},
+				"DOMAIN_SEPARATOR": {[32]byte(tokenDomainSeparator("USD Coin", "2", 1, testToken))},
+			},
+		},
+		Permit2Address: {
+			abi:     parsedPermit2ABI,
+			outputs: map[string][]interface{}{"allowance": {big.NewInt(0), big.NewInt(0), big.NewInt(5)}},
+		},
+	}
+}
+
+// newTokenServer 模拟 tokenContracts 中的合约
+func newTokenServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return newContractServer(t, tokenContracts(), nil)
+}
+
+func TestERC20_Permit(t *testing.T) {
+	eth := eth_helper.NewEthHelper(newTokenServer(t).URL)
+	defer eth.Close()
+	s, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	spender := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	deadline := time.Unix(1900000000, 0)
+
+	permit, err := NewErc20(eth, testToken).Permit(context.Background(), s, spender, decimal.RequireFromString("12.5"), deadline)
+	if err != nil {
+		t.Fatalf("Permit() error = %v", err)
+	}
+	if permit.Value.Int64() != 12500000 || permit.Nonce.Int64() != 3 || permit.Deadline.Int64() != deadline.Unix() {
+		t.Errorf("Permit() value = %v nonce = %v deadline = %v", permit.Value, permit.Nonce, permit.Deadline)
+	}
+	structHash := crypto.Keccak256(
+		crypto.Keccak256([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)")),
+		common.LeftPadBytes(s.Address().Bytes(), 32),
+		common.LeftPadBytes(spender.Bytes(), 32),
+		common.LeftPadBytes(permit.Value.Bytes(), 32),
+		common.LeftPadBytes(permit.Nonce.Bytes(), 32),
+		common.LeftPadBytes(permit.Deadline.Bytes(), 32),
+	)
+	digest := crypto.Keccak256([]byte("\x19\x01"), tokenDomainSeparator("USD Coin", "2", 1, testToken), structHash)
+	recovered, err := sign.RecoverHash(digest, append(append(permit.R[:], permit.S[:]...), permit.V))
+	if err != nil || recovered != s.Address() {
+		t.Errorf("Permit() signature recovered %s, %v, want %s", recovered, err, s.Address())
+	}
+	if _, err = permit.Calldata(); err != nil {
+		t.Errorf("Calldata() error = %v", err)
+	}
+}
+
+func TestPermit2_SignPermitSingle(t *testing.T) {
+	eth := eth_helper.NewEthHelper(newTokenServer(t).URL)
+	defer eth.Close()
+	s, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	spender := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	p := NewPermit2(eth)
+
+	permit, signature, err := p.SignPermitSingle(context.Background(), s, testToken, spender, big.NewInt(1000), time.Unix(1900000000, 0), time.Unix(1800000000, 0))
+	if err != nil {
+		t.Fatalf("SignPermitSingle() error = %v", err)
+	}
+	if permit.Details.Nonce != 5 {
+		t.Errorf("SignPermitSingle() nonce = %d, want 5", permit.Details.Nonce)
+	}
+	typedData, _ := p.TypedData(context.Background(), permit)
+	if recovered, err := sign.RecoverTypedData(typedData, signature); err != nil || recovered != s.Address() {
+		t.Errorf("SignPermitSingle() signature recovered %s, %v, want %s", recovered, err, s.Address())
+	}
+
+	transfer := PermitTransferFrom{
+		Permitted: TokenPermissions{Token: testToken, Amount: big.NewInt(1000)},
+		Spender:   spender,
+		Nonce:     big.NewInt(42),
+		Deadline:  big.NewInt(1800000000),
+	}
+	signature, err = p.SignPermitTransferFrom(context.Background(), s, transfer)
+	if err != nil {
+		t.Fatalf("SignPermitTransferFrom() error = %v", err)
+	}
+	typedData, _ = p.TypedData(context.Background(), transfer)
+	if recovered, err := sign.RecoverTypedData(typedData, signature); err != nil || recovered != s.Address() {
+		t.Errorf("SignPermitTransferFrom() signature recovered %s, %v, want %s", recovered, err, s.Address())
+	}
+}
@@ -0,0 +1,145 @@
+package eth_helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+)
+
+// DeterministicDeployer 确定性部署代理（Arachnid deterministic-deployment-proxy）的地址，在大多数 EVM 链上相同
+// 调用数据为 salt(32 字节) || initCode，合约地址为 CREATE2(DeterministicDeployer, salt, keccak256(initCode))
+var DeterministicDeployer = common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+
+var (
+	// ErrDeployerNotFound 当前链上没有部署确定性部署代理
+	ErrDeployerNotFound = errors.New("deterministic deployment proxy not found")
+	// ErrAlreadyDeployed 预测的地址上已经有合约
+	ErrAlreadyDeployed = errors.New("contract already deployed")
+	// ErrNoContractCode 部署交易成功但地址上没有合约代码
+	ErrNoContractCode = errors.New("no contract code at deployed address")
+)
+
+// DeployResult 合约部署结果
+type DeployResult struct {
+	Address common.Address // 合约地址
+	TxHash  common.Hash    // 部署交易哈希
+	Receipt *ReceiptResult // 部署交易回执
+}
+
+// CreateAddress 预测 sender 以 nonce 通过 CREATE 部署的合约地址
+func CreateAddress(sender common.Address, nonce uint64) common.Address {
+	return crypto.CreateAddress(sender, nonce)
+}
+
+// Create2Address 预测 deployer 以 salt 通过 CREATE2 部署的合约地址，initCodeHash 为 keccak256(initCode)
+func Create2Address(deployer common.Address, salt [32]byte, initCodeHash common.Hash) common.Address {
+	return crypto.CreateAddress2(deployer, salt, initCodeHash.Bytes())
+}
+
+// DeterministicAddress 预测通过 DeterministicDeployer 部署的合约地址
+func DeterministicAddress(salt [32]byte, initCode []byte) common.Address {
+	return Create2Address(DeterministicDeployer, salt, crypto.Keccak256Hash(initCode))
+}
+
+// InitCode 拼接合约字节码和 ABI 编码的构造参数，abiJSON 为空时不能传构造参数
+func InitCode(bytecode []byte, abiJSON string, constructorArgs ...interface{}) ([]byte, error) {
+	if abiJSON == "" {
+		if len(constructorArgs) > 0 {
+			return nil, errors.New("contract ABI is required to pack constructor arguments")
+		}
+		return common.CopyBytes(bytecode), nil
+	}
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %v", err)
+	}
+	args, err := parsed.Pack("", constructorArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack constructor arguments: %v", err)
+	}
+	return append(common.CopyBytes(bytecode), args...), nil
+}
+
+// Deploy 由 signer 发送创建合约的交易，等待上链后返回合约地址
+func (e *EthHelper) Deploy(ctx context.Context, signer eth_interface.SignerInterface, bytecode []byte, abiJSON string, constructorArgs ...interface{}) (*DeployResult, error) {
+	return e.DeployWithOptions(ctx, TxOptions{Signer: signer}, WaitOptions{}, bytecode, abiJSON, constructorArgs...)
+}
+
+// DeployWithOptions 按 opts 发送创建合约的交易，按 wait 等待上链后返回合约地址
+// 交易已发送但等待失败时，返回的结果中包含交易哈希
+func (e *EthHelper) DeployWithOptions(ctx context.Context, opts TxOptions, wait WaitOptions, bytecode []byte, abiJSON string, constructorArgs ...interface{}) (*DeployResult, error) {
+	initCode, err := InitCode(bytecode, abiJSON, constructorArgs...)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := e.transact(ctx, nil, decimal.Zero, initCode, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &DeployResult{TxHash: hash}
+	receipt, err := e.WaitForReceipt(ctx, hash, wait)
+	result.Receipt = receipt
+	if err != nil {
+		return result, err
+	}
+	result.Address = receipt.Receipt.ContractAddress
+	return result, e.checkDeployed(ctx, result.Address)
+}
+
+// DeployDeterministic 通过 DeterministicDeployer 以 salt 部署合约，合约地址与发送者和 nonce 无关
+// 预测地址上已有合约时返回 ErrAlreadyDeployed，结果中包含该地址
+func (e *EthHelper) DeployDeterministic(ctx context.Context, signer eth_interface.SignerInterface, salt [32]byte, bytecode []byte, abiJSON string, constructorArgs ...interface{}) (*DeployResult, error) {
+	return e.DeployDeterministicWithOptions(ctx, TxOptions{Signer: signer}, WaitOptions{}, salt, bytecode, abiJSON, constructorArgs...)
+}
+
+// DeployDeterministicWithOptions 按 opts 通过 DeterministicDeployer 部署合约
+func (e *EthHelper) DeployDeterministicWithOptions(ctx context.Context, opts TxOptions, wait WaitOptions, salt [32]byte, bytecode []byte, abiJSON string, constructorArgs ...interface{}) (*DeployResult, error) {
+	initCode, err := InitCode(bytecode, abiJSON, constructorArgs...)
+	if err != nil {
+		return nil, err
+	}
+	result := &DeployResult{Address: DeterministicAddress(salt, initCode)}
+	code, err := e.CodeAt(ctx, result.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) > 0 {
+		return result, fmt.Errorf("%w at %s", ErrAlreadyDeployed, result.Address.Hex())
+	}
+	code, err = e.CodeAt(ctx, DeterministicDeployer, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, ErrDeployerNotFound
+	}
+	data := append(salt[:], initCode...)
+	result.TxHash, err = e.transact(ctx, &DeterministicDeployer, decimal.Zero, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Receipt, err = e.WaitForReceipt(ctx, result.TxHash, wait)
+	if err != nil {
+		return result, err
+	}
+	return result, e.checkDeployed(ctx, result.Address)
+}
+
+// checkDeployed 检查地址上已经有合约代码
+func (e *EthHelper) checkDeployed(ctx context.Context, address common.Address) error {
+	code, err := e.CodeAt(ctx, address, nil)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("%w %s", ErrNoContractCode, address.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package eth_helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+)
+
+func TestCreateAddress(t *testing.T) {
+	sender := common.HexToAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+	tests := []struct {
+		nonce uint64
+		want  common.Address
+	}{
+		{0, common.HexToAddress("0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d")},
+		{1, common.HexToAddress("0x343c43a37d37dff08ae8c4a11544c718abb4fcf8")},
+		{2, common.HexToAddress("0xf778b86fa74e846c4f0a1fbd1335fe81c00a0c91")},
+	}
+	for _, tt := range tests {
+		if got := CreateAddress(sender, tt.nonce); got != tt.want {
+			t.Errorf("CreateAddress(%d) = %s, want %s", tt.nonce, got.Hex(), tt.want.Hex())
+		}
+	}
+}
+
+// TestCreate2Address 使用 EIP-1014 中的示例
+func TestCreate2Address(t *testing.T) {
+	tests := []struct {
+		name     string
+		deployer common.Address
+		salt     common.Hash
+		initCode []byte
+		want     common.Address
+	}{
+		{"zero", common.Address{}, common.Hash{}, common.FromHex("0x00"), common.HexToAddress("0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38")},
+		{"deadbeef", common.HexToAddress("0xdeadbeef00000000000000000000000000000000"), common.HexToHash("0x000000000000000000000000feed000000000000000000000000000000000000"), common.FromHex("0x00"), common.HexToAddress("0xD04116cDd17beBE565EB2422F2497E06cC1C9833")},
+		{"empty", common.Address{}, common.Hash{}, nil, common.HexToAddress("0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Create2Address(tt.deployer, tt.salt, crypto.Keccak256Hash(tt.initCode)); got != tt.want {
+				t.Errorf("Create2Address() = %s, want %s", got.Hex(), tt.want.Hex())
+			}
+		})
+	}
+}
+
+func TestInitCode(t *testing.T) {
+	abiJSON := `[{"inputs":[{"internalType":"uint256","name":"supply","type":"uint256"}],"stateMutability":"nonpayable","type":"constructor"}]`
+	bytecode := common.FromHex("0x6080")
+	got, err := InitCode(bytecode, abiJSON, common.Big2)
+	if err != nil {
+		t.Fatalf("InitCode() error = %v", err)
+	}
+	want := append(common.FromHex("0x6080"), common.LeftPadBytes([]byte{2}, 32)...)
+	if string(got) != string(want) {
+		t.Errorf("InitCode() = %x, want %x", got, want)
+	}
+	if _, err := InitCode(bytecode, abiJSON); err == nil {
+		t.Errorf("InitCode() without constructor arguments error = nil, want error")
+	}
+	if _, err := InitCode(bytecode, "", common.Big2); err == nil {
+		t.Errorf("InitCode() without ABI error = nil, want error")
+	}
+	if got, err := InitCode(bytecode, ""); err != nil || string(got) != string(bytecode) {
+		t.Errorf("InitCode() = %x, %v, want %x", got, err, bytecode)
+	}
+}
+
+// newCodeServer 模拟只支持 eth_getCode 的节点，codes 中的地址有合约代码
+func newCodeServer(t *testing.T, codes ...common.Address) *httptest.Server {
+	t.Helper()
+	return rpctest.NewServer(t, rpctest.Handlers{
+		"eth_getCode": func(params []json.RawMessage) (interface{}, error) {
+			var address common.Address
+			_ = json.Unmarshal(params[0], &address)
+			for _, item := range codes {
+				if item == address {
+					return "0x6080", nil
+				}
+			}
+			return "0x", nil
+		},
+	})
+}
+
+func TestEthHelper_DeployDeterministic(t *testing.T) {
+	bytecode := common.FromHex("0x6080")
+	var salt [32]byte
+	salt[31] = 1
+	predicted := DeterministicAddress(salt, bytecode)
+	tests := []struct {
+		name    string
+		codes   []common.Address
+		wantErr error
+	}{
+		{"already deployed", []common.Address{predicted, DeterministicDeployer}, ErrAlreadyDeployed},
+		{"no deployer", nil, ErrDeployerNotFound},
+		{"missing signer", []common.Address{DeterministicDeployer}, ErrMissingSigner},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eth := NewEthHelper(newCodeServer(t, tt.codes...).URL)
+			defer eth.Close()
+			result, err := eth.DeployDeterministicWithOptions(context.Background(), TxOptions{}, WaitOptions{}, salt, bytecode, "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeployDeterministicWithOptions() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == ErrAlreadyDeployed && result.Address != predicted {
+				t.Errorf("DeployDeterministicWithOptions() address = %s, want %s", result.Address.Hex(), predicted.Hex())
+			}
+		})
+	}
+}
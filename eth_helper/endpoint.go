@@ -0,0 +1,250 @@
+package eth_helper
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Endpoint 一个 RPC 节点及其轮询权重
+type Endpoint struct {
+	URL    string
+	Weight int // 轮询权重，小于等于 0 时按 1 处理
+}
+
+// ReadPolicy 读请求选择节点的策略
+type ReadPolicy int
+
+const (
+	// ReadPolicyRoundRobin 按权重轮询
+	ReadPolicyRoundRobin ReadPolicy = iota
+	// ReadPolicyLowestLatency 优先使用延迟最低的节点
+	ReadPolicyLowestLatency
+)
+
+// BroadcastMode 发送交易时的广播方式
+type BroadcastMode int
+
+const (
+	// BroadcastOne 发送到一个节点，失败时切换到下一个
+	BroadcastOne BroadcastMode = iota
+	// BroadcastAll 同时发送到所有节点，任意一个成功即视为成功
+	BroadcastAll
+)
+
+// EndpointOptions 多节点的选择、健康检查和广播配置
+type EndpointOptions struct {
+	ReadPolicy     ReadPolicy
+	BroadcastMode  BroadcastMode
+	MaxBlockLag    uint64        // 区块高度落后其他节点超过该值时视为不健康，0 表示不检查
+	HealthInterval time.Duration // 后台健康检查间隔，0 表示不启动后台检查
+}
+
+// endpoint 节点的连接和健康状态
+type endpoint struct {
+	url    string
+	weight int
+
+	mu      sync.Mutex
+	client  *ethclient.Client
+	healthy bool          // 最近一次请求是否成功
+	stale   bool          // 区块高度是否落后于其他节点
+	latency time.Duration // 请求耗时的滑动平均值
+	head    uint64
+}
+
+// getClient 返回节点的连接，首次调用时才建立连接
+func (ep *endpoint) getClient(ctx context.Context) (*ethclient.Client, error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	if ep.client != nil {
+		return ep.client, nil
+	}
+	client, err := dialEthClient(ctx, ep.url)
+	if err != nil {
+		return nil, err
+	}
+	ep.client = client
+	return ep.client, nil
+}
+
+// reset 丢弃已经失效的连接，下次调用时重新连接
+func (ep *endpoint) reset(client *ethclient.Client) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	if ep.client == client && client != nil {
+		ep.client.Close()
+		ep.client = nil
+	}
+}
+
+func (ep *endpoint) close() {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	if ep.client != nil {
+		ep.client.Close()
+		ep.client = nil
+	}
+}
+
+// record 记录一次请求的结果
+func (ep *endpoint) record(elapsed time.Duration, ok bool) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.healthy = ok
+	if !ok {
+		return
+	}
+	if ep.latency == 0 {
+		ep.latency = elapsed
+	} else {
+		ep.latency = (ep.latency*4 + elapsed) / 5
+	}
+}
+
+// status 返回节点是否可用以及请求延迟
+func (ep *endpoint) status() (usable bool, latency time.Duration) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return ep.healthy && !ep.stale, ep.latency
+}
+
+// NewEthHelperWithEndpoints 使用多个 RPC 节点创建 EthHelper
+// 读请求按 ReadPolicy 选择节点，节点连接异常或区块高度落后时自动切换到其他节点
+func NewEthHelperWithEndpoints(endpoints []Endpoint, opts EndpointOptions) *EthHelper {
+	e := NewEthHelper("")
+	if len(endpoints) > 0 {
+		e.rpcURL = endpoints[0].URL
+	}
+	e.endpointOptions = opts
+	for _, item := range endpoints {
+		weight := item.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		e.endpoints = append(e.endpoints, &endpoint{url: item.URL, weight: weight, healthy: true})
+	}
+	if opts.HealthInterval > 0 && len(e.endpoints) > 0 {
+		e.stopHealth = make(chan struct{})
+		go e.healthLoop(opts.HealthInterval, e.stopHealth)
+	}
+	return e
+}
+
+// pool 返回全部节点，只设置了 rpcURL 时按单节点处理
+func (e *EthHelper) pool() []*endpoint {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.endpoints) == 0 {
+		e.endpoints = []*endpoint{{url: e.rpcURL, weight: 1, healthy: true}}
+	}
+	return e.endpoints
+}
+
+// readOrder 返回读请求依次尝试的节点，健康节点在前
+func (e *EthHelper) readOrder() []*endpoint {
+	endpoints := e.pool()
+	if len(endpoints) == 1 {
+		return endpoints
+	}
+	ordered := make([]*endpoint, 0, len(endpoints))
+	switch e.endpointOptions.ReadPolicy {
+	case ReadPolicyLowestLatency:
+		ordered = append(ordered, endpoints...)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			_, li := ordered[i].status()
+			_, lj := ordered[j].status()
+			// 尚未测得延迟的节点排在后面
+			if li == 0 || lj == 0 {
+				return lj == 0 && li != 0
+			}
+			return li < lj
+		})
+	default:
+		total := 0
+		for _, ep := range endpoints {
+			total += ep.weight
+		}
+		e.mu.Lock()
+		slot := int(e.roundRobin % uint64(total))
+		e.roundRobin++
+		e.mu.Unlock()
+		start := 0
+		for i, ep := range endpoints {
+			if slot < ep.weight {
+				start = i
+				break
+			}
+			slot -= ep.weight
+		}
+		for i := range endpoints {
+			ordered = append(ordered, endpoints[(start+i)%len(endpoints)])
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		hi, _ := ordered[i].status()
+		hj, _ := ordered[j].status()
+		return hi && !hj
+	})
+	return ordered
+}
+
+// HealthCheck 检查所有节点的连通性和区块高度，落后超过 MaxBlockLag 的节点标记为不健康
+func (e *EthHelper) HealthCheck(ctx context.Context) {
+	endpoints := e.pool()
+	heads := make([]uint64, len(endpoints))
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			client, err := ep.getClient(ctx)
+			if err != nil {
+				errs[i] = err
+				ep.record(0, false)
+				return
+			}
+			start := time.Now()
+			heads[i], errs[i] = client.BlockNumber(ctx)
+			if errs[i] != nil && isConnectionError(errs[i]) {
+				ep.reset(client)
+			}
+			ep.record(time.Since(start), errs[i] == nil)
+		}(i, ep)
+	}
+	wg.Wait()
+	var best uint64
+	for i := range endpoints {
+		if errs[i] == nil && heads[i] > best {
+			best = heads[i]
+		}
+	}
+	for i, ep := range endpoints {
+		if errs[i] != nil {
+			continue
+		}
+		ep.mu.Lock()
+		ep.head = heads[i]
+		ep.stale = e.endpointOptions.MaxBlockLag > 0 && best-heads[i] > e.endpointOptions.MaxBlockLag
+		ep.mu.Unlock()
+	}
+}
+
+func (e *EthHelper) healthLoop(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		e.HealthCheck(ctx)
+		cancel()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package eth_helper
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+)
+
+// newRPCServer 启动一个只返回固定区块高度的 JSON-RPC 服务
+func newRPCServer(t *testing.T, blockNumber uint64) *httptest.Server {
+	t.Helper()
+	return rpctest.NewServer(t, rpctest.Handlers{"eth_blockNumber": rpctest.Result(hexutil.Uint64(blockNumber))})
+}
+
+func TestEthHelper_Failover(t *testing.T) {
+	dead := newRPCServer(t, 1)
+	dead.Close()
+	live := newRPCServer(t, 100)
+	e := NewEthHelperWithEndpoints([]Endpoint{{URL: dead.URL}, {URL: live.URL}}, EndpointOptions{})
+	defer e.Close()
+	for i := 0; i < 3; i++ {
+		got, err := e.GetBlockNumber(context.Background())
+		if err != nil {
+			t.Fatalf("GetBlockNumber() error = %v", err)
+		}
+		if got != 100 {
+			t.Errorf("GetBlockNumber() got = %v, want 100", got)
+		}
+	}
+}
+
+func TestEthHelper_StaleEndpoint(t *testing.T) {
+	stale := newRPCServer(t, 10)
+	fresh := newRPCServer(t, 100)
+	e := NewEthHelperWithEndpoints([]Endpoint{{URL: stale.URL}, {URL: fresh.URL}}, EndpointOptions{MaxBlockLag: 5})
+	defer e.Close()
+	e.HealthCheck(context.Background())
+	for i := 0; i < 4; i++ {
+		got, err := e.GetBlockNumber(context.Background())
+		if err != nil {
+			t.Fatalf("GetBlockNumber() error = %v", err)
+		}
+		if got != 100 {
+			t.Errorf("GetBlockNumber() got = %v, want 100 from the fresh endpoint", got)
+		}
+	}
+}
+
+func TestEthHelper_WeightedRoundRobin(t *testing.T) {
+	e := NewEthHelperWithEndpoints([]Endpoint{{URL: "http://a", Weight: 3}, {URL: "http://b", Weight: 1}}, EndpointOptions{})
+	counts := make(map[string]int)
+	for i := 0; i < 8; i++ {
+		counts[e.readOrder()[0].url]++
+	}
+	if counts["http://a"] != 6 || counts["http://b"] != 2 {
+		t.Errorf("readOrder() picked %v, want a:6 b:2", counts)
+	}
+}
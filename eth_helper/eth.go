@@ -2,10 +2,10 @@ package eth_helper
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
-	"log"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,18 +15,33 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/shopspring/decimal"
 	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+	"github.com/web3coderecho/web3_helper/eth_helper/revert"
 	"github.com/web3coderecho/web3_helper/utils"
 )
 
 type EthHelper struct {
-	rpcURL   string
-	chainId  *big.Int
-	gasPrice eth_interface.GasPriceInterface
+	rpcURL    string
+	chainId   *big.Int
+	gasPrice  eth_interface.GasPriceInterface
+	txType    TxType
+	batchSize int
+	priceBump int64
+
+	mu                  sync.Mutex
+	endpoints           []*endpoint
+	endpointOptions     EndpointOptions
+	roundRobin          uint64
+	stopHealth          chan struct{}
+	middlewares         []Middleware
+	dynamicFeeSupported *bool
+	nonceManager        *NonceManager
+	revertDecoder       *revert.Decoder
+	replacements        replacementTracker
 }
 
 func NewEthHelper(rpcURL string) *EthHelper {
 	return &EthHelper{
-		rpcURL: rpcURL,
+		rpcURL:  rpcURL,
 		chainId: big.NewInt(0),
 	}
 }
@@ -35,9 +50,20 @@ func (e *EthHelper) SetGasPrice(gasPrice eth_interface.GasPriceInterface) {
 	e.gasPrice = gasPrice
 }
 
+// GetGasPrice 返回传统交易使用的 gasPrice
+// 未设置自定义策略时，支持 EIP-1559 的链使用 baseFee + tip，否则使用节点建议的 gasPrice
 func (e *EthHelper) GetGasPrice(ctx context.Context) (*big.Int, error) {
 	if e.gasPrice == nil {
-		return e.FeeHistory(ctx, 20, []float64{25, 75})
+		fee, err := e.SuggestDynamicFee(ctx)
+		if err == nil {
+			return new(big.Int).Add(fee.BaseFee, fee.GasTipCap), nil
+		}
+		if !errors.Is(err, ErrDynamicFeeNotSupported) {
+			return nil, err
+		}
+		return withClient(ctx, e, func(client *ethclient.Client) (*big.Int, error) {
+			return client.SuggestGasPrice(ctx)
+		})
 	}
 	gasPrice, err := e.gasPrice.GetGasPrice()
 	if err != nil {
@@ -46,44 +72,30 @@ func (e *EthHelper) GetGasPrice(ctx context.Context) (*big.Int, error) {
 	return utils.ToWeiWithDecimals(gasPrice, 9), nil
 }
 
-// NewEthClient 初始化并连接到以太坊节点
+// NewEthClient 初始化并连接到以太坊节点，返回一个独立的客户端，由调用方负责关闭
+// 一般情况下应使用 GetClient 复用 EthHelper 持有的连接
 func (e *EthHelper) NewEthClient(ctx context.Context) (*ethclient.Client, error) {
-	client, err := ethclient.DialContext(ctx, e.rpcURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to Ethereum node: %v", err)
-	}
-	return client, nil
+	return dialEthClient(ctx, e.pool()[0].url)
 }
 
 func (e *EthHelper) GetBlockNumber(ctx context.Context) (uint64, error) {
-	client, err := e.NewEthClient(ctx)
-	if err != nil {
-		return 0, err
-	}
-	defer client.Close()
-	blockNumber, err := client.BlockNumber(ctx)
+	blockNumber, err := withClient(ctx, e, func(client *ethclient.Client) (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to get block number: %v", err)
 	}
 	return blockNumber, nil
 }
 func (e *EthHelper) GetBlockByNumber(ctx context.Context, blockNumber int64) (*types.Block, error) {
-	client, err := e.NewEthClient(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer client.Close()
-	return client.BlockByNumber(ctx, big.NewInt(blockNumber))
+	return withClient(ctx, e, func(client *ethclient.Client) (*types.Block, error) {
+		return client.BlockByNumber(ctx, big.NewInt(blockNumber))
+	})
 }
 
+// EstimateGas 估算交易需要的 gas，交易会回滚时返回 *revert.RevertError
 func (e *EthHelper) EstimateGas(ctx context.Context, from, to common.Address, data []byte, value decimal.Decimal) (uint64, error) {
-	client, err := e.NewEthClient(ctx)
-	if err != nil {
-		return 0, err
-	}
-	defer client.Close()
-
-	return client.EstimateGas(ctx, ethereum.CallMsg{
+	return e.estimateCall(ctx, ethereum.CallMsg{
 		From:  from,
 		To:    &to,
 		Data:  data,
@@ -92,12 +104,9 @@ func (e *EthHelper) EstimateGas(ctx context.Context, from, to common.Address, da
 }
 
 func (e *EthHelper) GetBalance(ctx context.Context, address common.Address) (decimal.Decimal, error) {
-	client, err := e.NewEthClient(ctx)
-	if err != nil {
-		return decimal.Zero, err
-	}
-	defer client.Close()
-	balance, err := client.BalanceAt(ctx, address, nil)
+	balance, err := withClient(ctx, e, func(client *ethclient.Client) (*big.Int, error) {
+		return client.BalanceAt(ctx, address, nil)
+	})
 	if err != nil {
 		return decimal.Zero, err
 	}
@@ -105,199 +114,310 @@ func (e *EthHelper) GetBalance(ctx context.Context, address common.Address) (dec
 }
 
 func (e *EthHelper) GetChainId(ctx context.Context) (*big.Int, error) {
-	client, err := e.NewEthClient(ctx)
+	e.mu.Lock()
+	chainId := e.chainId
+	e.mu.Unlock()
+	if chainId != nil && chainId.Cmp(big.NewInt(0)) > 0 {
+		return chainId, nil
+	}
+	chainId, err := withClient(ctx, e, func(client *ethclient.Client) (*big.Int, error) {
+		return client.ChainID(ctx)
+	})
 	if err != nil {
 		return e.chainId, err
 	}
-	defer client.Close()
-	if e.chainId == nil || e.chainId.Cmp(big.NewInt(0)) <= 0 {
-		chainId, err := client.ChainID(ctx)
-		if err != nil {
-			return e.chainId, err
-		}
-		e.chainId = chainId
-	}
-	return e.chainId, nil
+	e.mu.Lock()
+	e.chainId = chainId
+	e.mu.Unlock()
+	return chainId, nil
 }
 
 func (e *EthHelper) GetTransactionCount(ctx context.Context, address common.Address) (uint64, error) {
-	client, err := e.NewEthClient(ctx)
-	if err != nil {
-		return 0, err
-	}
-	defer client.Close()
-	return client.PendingNonceAt(ctx, address)
+	return withClient(ctx, e, func(client *ethclient.Client) (uint64, error) {
+		return client.PendingNonceAt(ctx, address)
+	})
 }
 
 func (e *EthHelper) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
-	client, err := e.NewEthClient(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer client.Close()
-	return client.TransactionReceipt(ctx, txHash)
+	return withClient(ctx, e, func(client *ethclient.Client) (*types.Receipt, error) {
+		return client.TransactionReceipt(ctx, txHash)
+	})
 }
 
 func (e *EthHelper) GetTransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
-	client, err := e.NewEthClient(ctx)
-	if err != nil {
-		return nil, false, err
-	}
-	defer client.Close()
-	return client.TransactionByHash(ctx, txHash)
+	var isPending bool
+	tx, err := withClient(ctx, e, func(client *ethclient.Client) (*types.Transaction, error) {
+		tx, pending, err := client.TransactionByHash(ctx, txHash)
+		isPending = pending
+		return tx, err
+	})
+	return tx, isPending, err
 }
 
 // TransferETH 发送 ETH 转账交易
 func (e *EthHelper) TransferETH(
 	ctx context.Context,
-	from common.Address,
-	privateKey *ecdsa.PrivateKey,
+	signer eth_interface.SignerInterface,
 	to common.Address,
 	amount decimal.Decimal,
 ) (common.Hash, error) {
-	return e.Transaction(ctx, from, privateKey, to, amount, 0, common.Big0, 0, nil)
+	return e.Transaction(ctx, signer, to, amount, 0, common.Big0, nil, nil)
 }
+
+// CheckTransactionStatus 等待交易上链（最长 60 秒），交易失败时返回错误
 func (e *EthHelper) CheckTransactionStatus(txHash common.Hash) error {
-	resultCh := make(chan bool, 1)
-	var wg sync.WaitGroup
-	wg.Add(1)
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-	go func() {
-		defer wg.Done()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				tx, err := e.GetTransactionReceipt(ctx, txHash)
-				if err != nil {
-					log.Printf("Error getting transaction status: %v", err)
-					time.Sleep(3 * time.Second)
-					continue
-				}
-				if tx.Status == types.ReceiptStatusSuccessful {
-					resultCh <- true
-				} else {
-					resultCh <- false
-				}
-				return
-			}
-		}
-	}()
-	wg.Wait()
-	select {
-	case result := <-resultCh:
-		if !result {
-			return fmt.Errorf("transaction failed %s", txHash.String())
-		}
-		return nil
-	case <-ctx.Done():
-		return fmt.Errorf("timeout waiting for transaction status %s", txHash.String())
-	}
+	_, err := e.WaitForReceipt(context.Background(), txHash, WaitOptions{
+		Confirmations: 1,
+		PollInterval:  3 * time.Second,
+		Timeout:       60 * time.Second,
+	})
+	return err
 }
+
+// Transaction 构造、签名并发送交易
+// 交易类型由 SetTxType 决定，默认在支持 EIP-1559 的链上发送 DynamicFeeTx，否则发送 LegacyTx
+// gasPrice 大于 0 时，传统交易作为 gasPrice 使用，EIP-1559 交易作为 maxFeePerGas 上限使用
+// nonce 为 nil 时由 NonceManager 分配，发送失败时归还
+// 交易由 signer 签名，发送地址为 signer.Address()
 func (e *EthHelper) Transaction(
 	ctx context.Context,
-	from common.Address,
-	privateKey *ecdsa.PrivateKey,
+	signer eth_interface.SignerInterface,
 	to common.Address,
 	amount decimal.Decimal,
 	gasLimit uint64,
 	gasPrice *big.Int,
-	nonce uint64,
+	nonce *uint64,
 	data []byte,
 ) (common.Hash, error) {
-	limit, price, _, err := e.Check(ctx, from, to, data, amount)
+	return e.Transact(ctx, to, amount, data, TxOptions{
+		Signer:   signer,
+		GasLimit: gasLimit,
+		GasPrice: gasPrice,
+		Nonce:    nonce,
+	})
+}
+
+// Transact 按 opts 构造、签名并发送交易，opts.Signer 必须设置
+func (e *EthHelper) Transact(ctx context.Context, to common.Address, amount decimal.Decimal, data []byte, opts TxOptions) (common.Hash, error) {
+	return e.transact(ctx, &to, amount, data, opts)
+}
+
+// transact 构造、签名并发送交易，to 为 nil 时发送创建合约的交易
+func (e *EthHelper) transact(ctx context.Context, to *common.Address, amount decimal.Decimal, data []byte, opts TxOptions) (common.Hash, error) {
+	if opts.Signer == nil {
+		return common.Hash{}, ErrMissingSigner
+	}
+	signer := opts.Signer
+	from := signer.Address()
+	unsigned, managed, err := e.buildTx(ctx, from, to, amount, data, opts)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	newGasPrice := big.NewInt(0)
-	if gasPrice.Cmp(big.NewInt(0)) <= 0 || gasPrice.Sign() == 0 {
-		newGasPrice = price
-	}else{
-		newGasPrice = gasPrice
+	return e.signAndSend(ctx, signer, unsigned, managed)
+}
+
+// signAndSend 签名并发送 buildTx 构造的交易，失败时归还 NonceManager 分配的 nonce
+func (e *EthHelper) signAndSend(ctx context.Context, signer eth_interface.SignerInterface, unsigned *UnsignedTx, managed bool) (common.Hash, error) {
+	from := unsigned.From
+	signedTx, err := signer.SignTx(ctx, unsigned.Tx, unsigned.ChainID)
+	if err != nil {
+		if managed {
+			_ = e.NonceManager().Release(from, unsigned.Tx.Nonce())
+		}
+		return common.Hash{}, fmt.Errorf("failed to sign transaction: %v", err)
+	}
+	hash, err := e.SendTransaction(ctx, signedTx)
+	if managed {
+		e.reclaimNonce(ctx, from, unsigned.Tx.Nonce(), err)
+	}
+	return hash, err
+}
+
+// buildTx 解析交易类型、gasLimit、手续费、chainId 和 nonce，构造未签名交易
+// opts.Signer 不会被使用，managed 表示 nonce 由 NonceManager 分配
+func (e *EthHelper) buildTx(ctx context.Context, from common.Address, to *common.Address, amount decimal.Decimal, data []byte, opts TxOptions) (unsigned *UnsignedTx, managed bool, err error) {
+	gasLimit, gasPrice, nonce := opts.GasLimit, opts.GasPrice, opts.Nonce
+	// 1. 确定交易类型
+	txType := opts.TxType
+	if txType == TxTypeAuto {
+		txType = e.txType
+	}
+	txType, err = e.resolveTxType(ctx, txType)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to resolve transaction type: %v", err)
+	}
+	// 2. 将 decimal.Decimal 转换为 *big.Int（Wei），生成访问列表并估算 gasLimit
+	value := utils.ToEther(amount)
+	msg := ethereum.CallMsg{From: from, To: to, Data: data, Value: value, AccessList: opts.AccessList}
+	if len(msg.AccessList) == 0 && opts.AutoAccessList {
+		msg.AccessList = e.accessListFor(ctx, msg)
+	}
+	limit, err := e.estimateCall(ctx, msg)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to estimate gas: %w", err)
 	}
 	if gasLimit <= limit {
 		gasLimit = limit
 	}
-	if nonce == 0 {
-		client, err := e.NewEthClient(ctx)
+	// 4. 获取 chainID
+	chainID, err := e.GetChainId(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	// 5. 计算手续费
+	var (
+		fee      *DynamicFee
+		newPrice *big.Int
+	)
+	switch txType {
+	case TxTypeDynamicFee:
+		fee, err = e.dynamicFeeFor(ctx, opts)
 		if err != nil {
-			return common.Hash{}, err
+			return nil, false, err
 		}
-		defer client.Close()
-		nonce, err = client.PendingNonceAt(ctx, from)
+		newPrice = fee.GasFeeCap
+	default:
+		newPrice = gasPrice
+		if newPrice == nil || newPrice.Sign() <= 0 {
+			newPrice, err = e.GetGasPrice(ctx)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to get gas price: %v", err)
+			}
+		}
+	}
+	if _, err = e.checkBalance(ctx, from, amount, gasLimit, newPrice); err != nil {
+		return nil, false, err
+	}
+	// 6. 分配 nonce
+	managed = nonce == nil
+	if managed {
+		n, err := e.NonceManager().Next(ctx, from)
 		if err != nil {
-			return common.Hash{}, fmt.Errorf("failed to get nonce: %v", err)
+			return nil, false, fmt.Errorf("failed to get nonce: %v", err)
 		}
+		nonce = &n
 	}
-	// 3. 将 decimal.Decimal 转换为 *big.Int（Wei）
-	value := utils.ToEther(amount)
-	// 5. 获取 chainID
-	chainID, err := e.GetChainId(ctx)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to get chain ID: %v", err)
+	// 7. 创建交易对象
+	var txData types.TxData
+	switch {
+	case txType == TxTypeDynamicFee:
+		txData = &types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      *nonce,
+			GasTipCap:  fee.GasTipCap,
+			GasFeeCap:  fee.GasFeeCap,
+			Gas:        gasLimit,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: msg.AccessList,
+		}
+	case txType == TxTypeAccessList || len(msg.AccessList) > 0:
+		// 传统交易不能携带访问列表，有访问列表时改为 AccessListTx 发送
+		txData = &types.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      *nonce,
+			GasPrice:   newPrice,
+			Gas:        gasLimit,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: msg.AccessList,
+		}
+	default:
+		txData = &types.LegacyTx{
+			Nonce:    *nonce,
+			To:       to,
+			Value:    value,
+			Gas:      gasLimit,
+			GasPrice: newPrice,
+			Data:     data,
+		}
 	}
-	// 4. 创建交易对象
-	tx := types.NewTx(&types.LegacyTx{
-		Nonce:    nonce,
-		To:       &to,
-		Value:    value,
-		Gas:      gasLimit,
-		GasPrice: newGasPrice,
-		Data:     data,
-	})
-	// 6. 使用私钥签名交易
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to sign transaction: %v", err)
+	return &UnsignedTx{From: from, ChainID: chainID, Tx: types.NewTx(txData)}, managed, nil
+}
+
+// reclaimNonce 交易发送后处理已分配的 nonce
+// 发送成功或交易池已有相同 nonce 时 nonce 已被占用；nonce 已上链时以链上为准重新同步；
+// 节点明确拒绝时归还 nonce；连接异常时交易可能已广播，不做处理
+func (e *EthHelper) reclaimNonce(ctx context.Context, from common.Address, nonce uint64, err error) {
+	switch {
+	case err == nil || isNonceInPoolError(err):
+		e.NonceManager().sent(from, nonce)
+	case isNonceTooLowError(err):
+		_ = e.NonceManager().Resync(ctx, from)
+	case isConnectionError(err) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled):
+	default:
+		_ = e.NonceManager().Release(from, nonce)
 	}
-	return e.SendTransaction(ctx, signedTx)
 }
 
 func (e *EthHelper) Check(ctx context.Context, from, to common.Address, data []byte, amount decimal.Decimal) (gasLimit uint64, gasPrice *big.Int, gas decimal.Decimal, err error) {
 	gasLimit, err = e.EstimateGas(ctx, from, to, data, amount)
 	if err != nil {
-		return 0, nil, decimal.Zero, fmt.Errorf("failed to estimate gas: %v", err)
+		return 0, nil, decimal.Zero, fmt.Errorf("failed to estimate gas: %w", err)
 	}
 	gasPrice, err = e.GetGasPrice(ctx)
-	gasFee := big.NewInt(0)
-	gasFee.Mul(gasPrice, big.NewInt(int64(gasLimit)))
+	if err != nil {
+		return 0, nil, decimal.Zero, fmt.Errorf("failed to get gas price: %v", err)
+	}
+	gas, err = e.checkBalance(ctx, from, amount, gasLimit, gasPrice)
+	return gasLimit, gasPrice, gas, err
+}
+
+// checkBalance 检查 from 的余额是否足够支付 amount 和最高手续费，返回需要的总金额
+func (e *EthHelper) checkBalance(ctx context.Context, from common.Address, amount decimal.Decimal, gasLimit uint64, gasPrice *big.Int) (decimal.Decimal, error) {
+	gasFee := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
 	fromBalance, err := e.GetBalance(ctx, from)
-	gas = utils.FromEther(gasFee).Add(amount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	gas := utils.FromEther(gasFee).Add(amount)
 	if gas.Cmp(fromBalance) >= 0 {
-		gas.Sub(fromBalance)
-		err = fmt.Errorf("insufficient balance")
+		return gas, fmt.Errorf("insufficient balance")
 	}
-	return gasLimit, gasPrice, gas, err
+	return gas, nil
 }
 
 func (e *EthHelper) SendTransaction(ctx context.Context, tx *types.Transaction) (common.Hash, error) {
-	client, err := e.NewEthClient(ctx)
-	if err != nil {
-		return common.Hash{}, err
+	err := e.broadcast(ctx, func(client *ethclient.Client) error {
+		return client.SendTransaction(ctx, tx)
+	})
+	// 重试或多节点广播时交易可能已经在交易池中
+	if err != nil && strings.Contains(strings.ToLower(err.Error()), "already known") {
+		err = nil
+	}
+	if sidecar := tx.BlobTxSidecar(); sidecar != nil && err == nil {
+		e.replacements.addSidecar(tx.Hash(), sidecar)
 	}
-	defer client.Close()
-	err = client.SendTransaction(ctx, tx)
 	return tx.Hash(), err
 }
 
+// CallContract 执行只读合约调用，blockNumber 为 nil 时使用最新区块，调用回滚时返回 *revert.RevertError
+func (e *EthHelper) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	output, err := withClient(ctx, e, func(client *ethclient.Client) ([]byte, error) {
+		return client.CallContract(ctx, msg, blockNumber)
+	})
+	return output, e.decodeRevert(err)
+}
+
+// CodeAt 返回地址上的合约代码，普通地址返回空
+func (e *EthHelper) CodeAt(ctx context.Context, address common.Address, blockNumber *big.Int) ([]byte, error) {
+	return withClient(ctx, e, func(client *ethclient.Client) ([]byte, error) {
+		return client.CodeAt(ctx, address, blockNumber)
+	})
+}
+
 func (e *EthHelper) FilterLogs(ctx context.Context, filterQuery ethereum.FilterQuery) ([]types.Log, error) {
-	client, err := e.NewEthClient(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer client.Close()
-	return client.FilterLogs(ctx, filterQuery)
+	return withClient(ctx, e, func(client *ethclient.Client) ([]types.Log, error) {
+		return client.FilterLogs(ctx, filterQuery)
+	})
 }
 
 func (e *EthHelper) FeeHistory(ctx context.Context, blockCount uint64, rewardPercentiles []float64) (*big.Int, error) {
-	client, err := e.NewEthClient(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer client.Close()
-	res, err := client.FeeHistory(ctx, blockCount, nil, rewardPercentiles)
+	res, err := withClient(ctx, e, func(client *ethclient.Client) (*ethereum.FeeHistory, error) {
+		return client.FeeHistory(ctx, blockCount, nil, rewardPercentiles)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -320,3 +440,21 @@ func (e *EthHelper) estimateGasPrice(feeHistory *ethereum.FeeHistory) (maxPriori
 	// 构造 gas 参数
 	return new(big.Int).Set(avgTip)
 }
+
+// RevertDecoder 返回解析回滚原因使用的 Decoder，可以通过它注册自定义合约的 ABI
+func (e *EthHelper) RevertDecoder() *revert.Decoder {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.revertDecoder == nil {
+		e.revertDecoder = revert.NewDecoder()
+	}
+	return e.revertDecoder
+}
+
+// decodeRevert 把包含回滚数据的 RPC 错误转换为 *revert.RevertError
+func (e *EthHelper) decodeRevert(err error) error {
+	if revertErr, ok := e.RevertDecoder().FromError(err); ok {
+		return revertErr
+	}
+	return err
+}
@@ -0,0 +1,11 @@
+package eth_interface
+
+import "github.com/ethereum/go-ethereum/common"
+
+// NonceStoreInterface 持久化每个地址下一个可用的 nonce，使 nonce 在进程重启后依然连续
+type NonceStoreInterface interface {
+	// LoadNonce 读取地址下一个可用的 nonce，ok 为 false 表示没有记录
+	LoadNonce(address common.Address) (nonce uint64, ok bool, err error)
+	// SaveNonce 保存地址下一个可用的 nonce
+	SaveNonce(address common.Address, nonce uint64) error
+}
@@ -0,0 +1,29 @@
+package eth_interface
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// SignerInterface 签名器，调用方只持有签名器而不直接接触私钥
+// 私钥可以保存在内存、keystore 文件、HD 钱包或远程签名服务中
+type SignerInterface interface {
+	// Address 签名器对应的地址
+	Address() common.Address
+	// SignTx 使用 chainID 对应的签名规则签名交易
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignTypedData 按 EIP-712 签名结构化数据，返回 65 字节签名，V 为 27 或 28
+	SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error)
+}
+
+// HashSigner 可以直接签名原始哈希的签名器，Tron 交易和任意哈希签名需要这一能力
+// 远程签名服务（Clef、web3signer）只签名带前缀的消息，不实现该接口
+type HashSigner interface {
+	SignerInterface
+	// SignHash 签名 32 字节哈希，返回 65 字节 [R || S || V] 签名，V 为 0 或 1
+	SignHash(ctx context.Context, hash []byte) ([]byte, error)
+}
@@ -0,0 +1,130 @@
+package eth_helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// TxType 发送交易时使用的交易类型
+type TxType int
+
+const (
+	// TxTypeAuto 根据链是否支持 EIP-1559 自动选择
+	TxTypeAuto TxType = iota
+	// TxTypeLegacy 传统 gasPrice 交易
+	TxTypeLegacy
+	// TxTypeDynamicFee EIP-1559 动态手续费交易
+	TxTypeDynamicFee
+	// TxTypeAccessList EIP-2930 携带访问列表的 gasPrice 交易
+	TxTypeAccessList
+)
+
+// ErrDynamicFeeNotSupported 链上区块没有 baseFee 或 baseFee 为 0，不支持 EIP-1559
+var ErrDynamicFeeNotSupported = errors.New("chain does not support EIP-1559 dynamic fee transactions")
+
+// DynamicFee EIP-1559 交易的手续费参数
+type DynamicFee struct {
+	BaseFee   *big.Int // 下一个区块的 baseFee
+	GasTipCap *big.Int // maxPriorityFeePerGas
+	GasFeeCap *big.Int // maxFeePerGas
+}
+
+// SetTxType 指定发送交易时使用的交易类型，默认 TxTypeAuto
+func (e *EthHelper) SetTxType(txType TxType) {
+	e.txType = txType
+}
+
+// SupportsDynamicFee 判断链是否支持 EIP-1559，结果会被缓存
+// 部分链的区块带有恒为 0 的 baseFee，按不支持处理
+func (e *EthHelper) SupportsDynamicFee(ctx context.Context) (bool, error) {
+	e.mu.Lock()
+	supported := e.dynamicFeeSupported
+	e.mu.Unlock()
+	if supported != nil {
+		return *supported, nil
+	}
+	header, err := withClient(ctx, e, func(client *ethclient.Client) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, nil)
+	})
+	if err != nil {
+		return false, err
+	}
+	ok := header.BaseFee != nil && header.BaseFee.Sign() > 0
+	e.mu.Lock()
+	e.dynamicFeeSupported = &ok
+	e.mu.Unlock()
+	return ok, nil
+}
+
+// resolveTxType 返回本次交易实际使用的交易类型
+func (e *EthHelper) resolveTxType(ctx context.Context, txType TxType) (TxType, error) {
+	if txType != TxTypeAuto {
+		return txType, nil
+	}
+	supported, err := e.SupportsDynamicFee(ctx)
+	if err != nil {
+		return TxTypeAuto, err
+	}
+	if supported {
+		return TxTypeDynamicFee, nil
+	}
+	return TxTypeLegacy, nil
+}
+
+// SuggestDynamicFee 根据 FeeHistory 计算 EIP-1559 手续费
+// maxFeePerGas = 2 * baseFee + tip，可以承受连续几个区块 baseFee 上涨
+func (e *EthHelper) SuggestDynamicFee(ctx context.Context) (*DynamicFee, error) {
+	res, err := withClient(ctx, e, func(client *ethclient.Client) (*ethereum.FeeHistory, error) {
+		return client.FeeHistory(ctx, 20, nil, []float64{25, 75})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.BaseFee) == 0 || res.BaseFee[len(res.BaseFee)-1] == nil || res.BaseFee[len(res.BaseFee)-1].Sign() == 0 {
+		return nil, ErrDynamicFeeNotSupported
+	}
+	// FeeHistory 返回的最后一个 baseFee 是下一个区块的 baseFee
+	baseFee := new(big.Int).Set(res.BaseFee[len(res.BaseFee)-1])
+	tip := e.estimateGasPrice(res)
+	if tip == nil {
+		tip, err = withClient(ctx, e, func(client *ethclient.Client) (*big.Int, error) {
+			return client.SuggestGasTipCap(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+	feeCap.Add(feeCap, tip)
+	return &DynamicFee{
+		BaseFee:   baseFee,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+	}, nil
+}
+
+// dynamicFeeFor 计算交易的 EIP-1559 手续费
+// opts.GasTipCap 覆盖建议的 tip，opts.GasPrice 覆盖 maxFeePerGas 并限制 tip 不超过它
+func (e *EthHelper) dynamicFeeFor(ctx context.Context, opts TxOptions) (*DynamicFee, error) {
+	fee, err := e.SuggestDynamicFee(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest dynamic fee: %v", err)
+	}
+	if opts.GasTipCap != nil {
+		fee.GasTipCap = opts.GasTipCap
+		fee.GasFeeCap = new(big.Int).Add(new(big.Int).Mul(fee.BaseFee, big.NewInt(2)), opts.GasTipCap)
+	}
+	if gasPrice := opts.GasPrice; gasPrice != nil && gasPrice.Sign() > 0 {
+		fee.GasFeeCap = gasPrice
+		if fee.GasTipCap.Cmp(gasPrice) > 0 {
+			fee.GasTipCap = gasPrice
+		}
+	}
+	return fee, nil
+}
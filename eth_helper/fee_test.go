@@ -0,0 +1,102 @@
+package eth_helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+)
+
+// testFeeHistory 下一个区块 baseFee 为 2 gwei，两个区块 75% 百分位的 tip 为 3 gwei 和 5 gwei
+const testFeeHistory = `{"oldestBlock":"0x1","baseFeePerGas":["0x3b9aca00","0x77359400"],"gasUsedRatio":[0.5],"reward":[["0x3b9aca00","0xb2d05e00"],["0x3b9aca00","0x12a05f200"]]}`
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9))
+}
+
+func TestEthHelper_SuggestDynamicFee(t *testing.T) {
+	eth := NewEthHelper(rpctest.NewServer(t, rpctest.Handlers{
+		"eth_feeHistory": rpctest.Result(json.RawMessage(testFeeHistory)),
+	}).URL)
+	defer eth.Close()
+	fee, err := eth.SuggestDynamicFee(context.Background())
+	if err != nil {
+		t.Fatalf("SuggestDynamicFee() error = %v", err)
+	}
+	// tip 取 75% 百分位的平均值，maxFeePerGas = 2 * baseFee + tip
+	if fee.BaseFee.Cmp(gwei(2)) != 0 || fee.GasTipCap.Cmp(gwei(4)) != 0 || fee.GasFeeCap.Cmp(gwei(8)) != 0 {
+		t.Errorf("SuggestDynamicFee() = %s/%s/%s, want 2/4/8 gwei", fee.BaseFee, fee.GasTipCap, fee.GasFeeCap)
+	}
+
+	legacy := NewEthHelper(rpctest.NewServer(t, rpctest.Handlers{
+		"eth_feeHistory": rpctest.Result(json.RawMessage(`{"oldestBlock":"0x1","baseFeePerGas":["0x0","0x0"],"gasUsedRatio":[0.5],"reward":[["0x0","0x0"]]}`)),
+	}).URL)
+	defer legacy.Close()
+	if _, err := legacy.SuggestDynamicFee(context.Background()); !errors.Is(err, ErrDynamicFeeNotSupported) {
+		t.Errorf("SuggestDynamicFee() with zero baseFee error = %v, want ErrDynamicFeeNotSupported", err)
+	}
+}
+
+func TestEthHelper_ResolveTxType(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseFee *big.Int
+		txType  TxType
+		want    TxType
+	}{
+		{"auto with baseFee", gwei(1), TxTypeAuto, TxTypeDynamicFee},
+		{"auto without baseFee", nil, TxTypeAuto, TxTypeLegacy},
+		{"auto with zero baseFee", common.Big0, TxTypeAuto, TxTypeLegacy},
+		{"explicit legacy", gwei(1), TxTypeLegacy, TxTypeLegacy},
+		{"explicit access list", nil, TxTypeAccessList, TxTypeAccessList},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := &types.Header{Number: big.NewInt(10), Difficulty: common.Big0, BaseFee: tt.baseFee}
+			eth := NewEthHelper(rpctest.NewServer(t, rpctest.Handlers{
+				"eth_getBlockByNumber": rpctest.Result(header),
+			}).URL)
+			defer eth.Close()
+			got, err := eth.resolveTxType(context.Background(), tt.txType)
+			if err != nil || got != tt.want {
+				t.Errorf("resolveTxType() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEthHelper_DynamicFeeFor(t *testing.T) {
+	eth := NewEthHelper(rpctest.NewServer(t, rpctest.Handlers{
+		"eth_feeHistory": rpctest.Result(json.RawMessage(testFeeHistory)),
+	}).URL)
+	defer eth.Close()
+	tests := []struct {
+		name       string
+		opts       TxOptions
+		wantTip    *big.Int
+		wantFeeCap *big.Int
+	}{
+		{"suggested", TxOptions{}, gwei(4), gwei(8)},
+		{"tip override", TxOptions{GasTipCap: gwei(1)}, gwei(1), gwei(5)},
+		{"gas price caps fee", TxOptions{GasPrice: gwei(6)}, gwei(4), gwei(6)},
+		{"gas price below tip", TxOptions{GasPrice: gwei(3)}, gwei(3), gwei(3)},
+		{"tip and gas price", TxOptions{GasTipCap: gwei(1), GasPrice: gwei(6)}, gwei(1), gwei(6)},
+		{"zero gas price ignored", TxOptions{GasPrice: common.Big0}, gwei(4), gwei(8)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, err := eth.dynamicFeeFor(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("dynamicFeeFor() error = %v", err)
+			}
+			if fee.GasTipCap.Cmp(tt.wantTip) != 0 || fee.GasFeeCap.Cmp(tt.wantFeeCap) != 0 {
+				t.Errorf("dynamicFeeFor() tip %s, fee cap %s, want %s, %s", fee.GasTipCap, fee.GasFeeCap, tt.wantTip, tt.wantFeeCap)
+			}
+		})
+	}
+}
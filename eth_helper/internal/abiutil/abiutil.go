@@ -0,0 +1,30 @@
+// Package abiutil 在包初始化时解析内置的 ABI，解析失败说明代码有误，直接 panic
+package abiutil
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// MustParseABI 解析 JSON ABI
+func MustParseABI(data string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(data))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// MustArguments 按类型名构造未命名的参数列表，用于 abi.encode 和 abi.decode
+func MustArguments(typeNames ...string) abi.Arguments {
+	arguments := make(abi.Arguments, len(typeNames))
+	for i, name := range typeNames {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		arguments[i] = abi.Argument{Type: typ}
+	}
+	return arguments
+}
@@ -0,0 +1,236 @@
+package eth_helper
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Invoker 在 url 对应的节点上执行一次 RPC 调用
+type Invoker func(ctx context.Context, url string) error
+
+// Middleware 包装 RPC 调用，用于实现限流、重试等通用逻辑
+type Middleware func(next Invoker) Invoker
+
+// Use 注册中间件，所有 EthHelper 和合约封装的 RPC 调用都会经过中间件
+// 先注册的中间件在外层，例如 Use(Retry(...), RateLimit(...)) 时每次重试都会被限流
+func (e *EthHelper) Use(middlewares ...Middleware) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.middlewares = append(e.middlewares, middlewares...)
+}
+
+// invoke 经过中间件执行 call
+func (e *EthHelper) invoke(ctx context.Context, url string, call func() error) error {
+	e.mu.Lock()
+	middlewares := e.middlewares
+	e.mu.Unlock()
+	invoker := func(ctx context.Context, url string) error {
+		return call()
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		invoker = middlewares[i](invoker)
+	}
+	return invoker(ctx, url)
+}
+
+// RetryPolicy 重试策略，重试间隔按指数增长并加入随机抖动
+type RetryPolicy struct {
+	MaxAttempts int           // 最多尝试次数，包含第一次调用
+	BaseDelay   time.Duration // 第一次重试前的等待时间
+	MaxDelay    time.Duration // 单次等待的上限
+}
+
+// DefaultRetryPolicy 默认重试策略
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// Retry 对可重试的错误按 policy 重试
+func Retry(policy RetryPolicy) Middleware {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	return func(next Invoker) Invoker {
+		return func(ctx context.Context, url string) error {
+			var err error
+			for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+				if attempt > 0 {
+					timer := time.NewTimer(policy.backoff(attempt))
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return err
+					case <-timer.C:
+					}
+				}
+				err = next(ctx, url)
+				if err == nil || !IsRetryableError(err) {
+					return err
+				}
+			}
+			return err
+		}
+	}
+}
+
+// backoff 返回第 attempt 次重试前的等待时间，在 [delay/2, delay] 之间随机
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// RateLimit 按节点限流，每个节点每秒最多 rps 个请求，允许 burst 个请求的突发
+// rps 不是正数（包括 NaN）时不限流，burst 小于 1 时按 1 处理
+func RateLimit(rps float64, burst int) Middleware {
+	if !(rps > 0) {
+		return func(next Invoker) Invoker {
+			return next
+		}
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	var (
+		mu      sync.Mutex
+		buckets = make(map[string]*tokenBucket)
+	)
+	return func(next Invoker) Invoker {
+		return func(ctx context.Context, url string) error {
+			mu.Lock()
+			bucket, ok := buckets[url]
+			if !ok {
+				bucket = newTokenBucket(rps, burst)
+				buckets[url] = bucket
+			}
+			mu.Unlock()
+			if err := bucket.wait(ctx); err != nil {
+				return err
+			}
+			return next(ctx, url)
+		}
+	}
+}
+
+// tokenBucket 令牌桶
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒生成的令牌数
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait 阻塞直到取得一个令牌或 ctx 结束
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// nonRetryableMessages 节点明确拒绝、重试也不会成功的错误
+var nonRetryableMessages = []string{
+	"execution reverted",
+	"nonce too low",
+	"nonce too high",
+	"already known",
+	"replacement transaction underpriced",
+	"insufficient funds",
+	"intrinsic gas too low",
+	"gas required exceeds allowance",
+	"invalid sender",
+	"invalid argument",
+}
+
+// retryableMessages 节点临时不可用或限流导致的错误
+var retryableMessages = []string{
+	"timeout",
+	"timed out",
+	"too many requests",
+	"rate limit",
+	"limit exceeded",
+	"header not found",
+	"unknown block",
+	"service unavailable",
+	"bad gateway",
+	"try again",
+}
+
+// IsRetryableError 判断 RPC 错误是否可以重试：超时、限流、节点尚未同步到指定区块等
+// 交易回滚、nonce 错误、余额不足等错误重试也不会成功，返回 false
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, item := range nonRetryableMessages {
+		if strings.Contains(msg, item) {
+			return false
+		}
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	if isConnectionError(err) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	for _, item := range retryableMessages {
+		if strings.Contains(msg, item) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package eth_helper
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rate limited", rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}, true},
+		{"server error", rpc.HTTPError{StatusCode: 502, Status: "502 Bad Gateway"}, true},
+		{"bad request", rpc.HTTPError{StatusCode: 400, Status: "400 Bad Request"}, false},
+		{"limit exceeded", errors.New("limit exceeded"), true},
+		{"header not found", errors.New("header not found"), true},
+		{"reverted", errors.New("execution reverted: ERC20: transfer amount exceeds balance"), false},
+		{"nonce too low", errors.New("nonce too low"), false},
+		{"canceled", context.Canceled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableError(tt.err); got != tt.want {
+				t.Errorf("IsRetryableError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls int
+	}{
+		{"retryable", errors.New("header not found"), 3},
+		{"non retryable", errors.New("execution reverted"), 1},
+		{"success", nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			invoker := Retry(policy)(func(ctx context.Context, url string) error {
+				calls++
+				return tt.err
+			})
+			if err := invoker(context.Background(), "http://node"); !errors.Is(err, tt.err) {
+				t.Errorf("Retry() error = %v, want %v", err, tt.err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Retry() calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	invoker := RateLimit(50, 1)(func(ctx context.Context, url string) error {
+		return nil
+	})
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := invoker(context.Background(), "http://node"); err != nil {
+			t.Fatalf("RateLimit() error = %v", err)
+		}
+	}
+	// 第一个请求消耗突发令牌，其余 4 个每个至少等待 20ms
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("RateLimit() finished 5 calls in %v, want at least 70ms", elapsed)
+	}
+	// 不同节点使用各自的令牌桶
+	start = time.Now()
+	if err := invoker(context.Background(), "http://other"); err != nil {
+		t.Fatalf("RateLimit() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("RateLimit() delayed a fresh endpoint by %v", elapsed)
+	}
+
+	// rps 不是正数时不限流，也不会忙等
+	for _, rps := range []float64{0, -1, math.NaN()} {
+		invoker = RateLimit(rps, 0)(func(ctx context.Context, url string) error {
+			return nil
+		})
+		start = time.Now()
+		for i := 0; i < 100; i++ {
+			if err := invoker(context.Background(), "http://node"); err != nil {
+				t.Fatalf("RateLimit(%v) error = %v", rps, err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+			t.Errorf("RateLimit(%v) took %v for 100 calls, want no limit", rps, elapsed)
+		}
+	}
+}
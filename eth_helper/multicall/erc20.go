@@ -0,0 +1,171 @@
+package multicall
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+	"github.com/web3coderecho/web3_helper/utils"
+)
+
+// TokenBalance 某个地址持有某个 ERC20 代币的余额
+type TokenBalance struct {
+	Token   common.Address
+	Holder  common.Address
+	Raw     *big.Int        // 链上原始数值
+	Balance decimal.Decimal // 按代币精度换算后的余额
+	Err     error
+}
+
+// TokenMetadata ERC20 代币的基本信息
+type TokenMetadata struct {
+	Token       common.Address
+	Name        string
+	Symbol      string
+	Decimals    int
+	TotalSupply decimal.Decimal
+	Err         error
+}
+
+// ERC20Balances 一次调用查询多个地址在多个代币上的余额，结果按 tokens × holders 的顺序排列
+func (m *Multicall) ERC20Balances(ctx context.Context, tokens, holders []common.Address) ([]TokenBalance, error) {
+	erc20ABI, err := erc20.Erc20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	calls := make([]ContractCall, 0, len(tokens)*(len(holders)+1))
+	for _, token := range tokens {
+		calls = append(calls, ContractCall{Target: token, ABI: erc20ABI, Method: "decimals", AllowFailure: true})
+		for _, holder := range holders {
+			calls = append(calls, ContractCall{Target: token, ABI: erc20ABI, Method: "balanceOf", Args: []interface{}{holder}, AllowFailure: true})
+		}
+	}
+	results, err := m.CallContracts(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	balances := make([]TokenBalance, 0, len(tokens)*len(holders))
+	idx := 0
+	for _, token := range tokens {
+		decimals, decimalsErr := uint8Value(results[idx])
+		idx++
+		for _, holder := range holders {
+			item := TokenBalance{Token: token, Holder: holder}
+			item.Raw, item.Err = bigValue(results[idx])
+			idx++
+			if item.Err == nil && decimalsErr != nil {
+				item.Err = fmt.Errorf("failed to get decimals: %v", decimalsErr)
+			}
+			if item.Err == nil {
+				item.Balance = utils.FromWeiWithDecimals(item.Raw, int(decimals))
+			}
+			balances = append(balances, item)
+		}
+	}
+	return balances, nil
+}
+
+// ERC20Metadata 一次调用查询多个代币的名称、符号、精度和总量
+// 兼容 name/symbol 返回 bytes32 的早期代币
+func (m *Multicall) ERC20Metadata(ctx context.Context, tokens []common.Address) ([]TokenMetadata, error) {
+	erc20ABI, err := erc20.Erc20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	methods := []string{"name", "symbol", "decimals", "totalSupply"}
+	calls := make([]Call, 0, len(tokens)*len(methods))
+	for _, token := range tokens {
+		for _, method := range methods {
+			data, err := erc20ABI.Pack(method)
+			if err != nil {
+				return nil, err
+			}
+			calls = append(calls, Call{Target: token, AllowFailure: true, CallData: data})
+		}
+	}
+	results, err := m.Aggregate3(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	metadata := make([]TokenMetadata, len(tokens))
+	for i, token := range tokens {
+		item := TokenMetadata{Token: token}
+		base := i * len(methods)
+		name, nameErr := stringValue(erc20ABI, "name", results[base])
+		symbol, symbolErr := stringValue(erc20ABI, "symbol", results[base+1])
+		decimals, decimalsErr := uint8Value(unpack(erc20ABI, "decimals", results[base+2]))
+		totalSupply, totalSupplyErr := bigValue(unpack(erc20ABI, "totalSupply", results[base+3]))
+		for _, err := range []error{nameErr, symbolErr, decimalsErr, totalSupplyErr} {
+			if err != nil {
+				item.Err = err
+				break
+			}
+		}
+		if item.Err == nil {
+			item.Name = name
+			item.Symbol = symbol
+			item.Decimals = int(decimals)
+			item.TotalSupply = utils.FromWeiWithDecimals(totalSupply, int(decimals))
+		}
+		metadata[i] = item
+	}
+	return metadata, nil
+}
+
+func unpack(contractABI *abi.ABI, method string, result Result) CallResult {
+	if !result.Success {
+		return CallResult{Err: fmt.Errorf("%w: %s", ErrCallFailed, method)}
+	}
+	values, err := contractABI.Unpack(method, result.ReturnData)
+	return CallResult{Success: true, Values: values, Err: err}
+}
+
+// stringValue 解析返回 string 的调用，解析失败时按 bytes32 处理
+func stringValue(contractABI *abi.ABI, method string, result Result) (string, error) {
+	res := unpack(contractABI, method, result)
+	if res.Err == nil && len(res.Values) == 1 {
+		if value, ok := res.Values[0].(string); ok {
+			return value, nil
+		}
+	}
+	if result.Success && len(result.ReturnData) == 32 {
+		return string(bytes.TrimRight(result.ReturnData, "\x00")), nil
+	}
+	if res.Err != nil {
+		return "", res.Err
+	}
+	return "", fmt.Errorf("unexpected %s return value", method)
+}
+
+func uint8Value(res CallResult) (uint8, error) {
+	if res.Err != nil {
+		return 0, res.Err
+	}
+	if len(res.Values) != 1 {
+		return 0, fmt.Errorf("unexpected return values %v", res.Values)
+	}
+	value, ok := res.Values[0].(uint8)
+	if !ok {
+		return 0, fmt.Errorf("unexpected return value %v", res.Values[0])
+	}
+	return value, nil
+}
+
+func bigValue(res CallResult) (*big.Int, error) {
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	if len(res.Values) != 1 {
+		return nil, fmt.Errorf("unexpected return values %v", res.Values)
+	}
+	value, ok := res.Values[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected return value %v", res.Values[0])
+	}
+	return value, nil
+}
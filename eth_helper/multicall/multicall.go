@@ -0,0 +1,158 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/internal/abiutil"
+)
+
+// Multicall3Address Multicall3 在绝大多数 EVM 链上的部署地址
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// DefaultMaxCalls 单次 aggregate3 调用默认包含的最大调用数
+const DefaultMaxCalls = 500
+
+// ErrCallFailed 允许失败的子调用执行失败
+var ErrCallFailed = errors.New("multicall: call failed")
+
+const multicall3ABI = `[{"inputs":[{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bool","name":"allowFailure","type":"bool"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall3.Call3[]","name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+var parsedABI = abiutil.MustParseABI(multicall3ABI)
+
+// Call aggregate3 的单个子调用
+type Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// Result aggregate3 的单个子调用结果
+type Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// ContractCall 基于 ABI 描述的子调用，由 CallContracts 负责编码和解码
+type ContractCall struct {
+	Target       common.Address
+	ABI          *abi.ABI
+	Method       string
+	Args         []interface{}
+	AllowFailure bool
+}
+
+// CallResult ContractCall 的解码结果
+type CallResult struct {
+	Success bool
+	Values  []interface{}
+	Err     error
+}
+
+// Multicall 通过 Multicall3 合约把多个只读调用合并为一次 eth_call
+type Multicall struct {
+	Address  common.Address
+	MaxCalls int // 单次 aggregate3 的最大调用数，超过时拆分为多次调用
+	eth      *eth_helper.EthHelper
+}
+
+// NewMulticall 使用标准 Multicall3 地址创建 Multicall
+func NewMulticall(eth *eth_helper.EthHelper) *Multicall {
+	return &Multicall{
+		Address:  Multicall3Address,
+		MaxCalls: DefaultMaxCalls,
+		eth:      eth,
+	}
+}
+
+// Aggregate3 执行 aggregate3，返回与 calls 一一对应的结果
+// AllowFailure 为 false 的子调用失败时整个 aggregate3 回滚并返回错误
+func (m *Multicall) Aggregate3(ctx context.Context, calls []Call) ([]Result, error) {
+	size := m.MaxCalls
+	if size <= 0 {
+		size = DefaultMaxCalls
+	}
+	results := make([]Result, 0, len(calls))
+	for start := 0; start < len(calls); start += size {
+		end := start + size
+		if end > len(calls) {
+			end = len(calls)
+		}
+		chunk, err := m.aggregate3(ctx, calls[start:end])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, chunk...)
+	}
+	return results, nil
+}
+
+func (m *Multicall) aggregate3(ctx context.Context, calls []Call) ([]Result, error) {
+	type call3 struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	}
+	args := make([]call3, len(calls))
+	for i, call := range calls {
+		args[i] = call3(call)
+	}
+	data, err := parsedABI.Pack("aggregate3", args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack aggregate3: %v", err)
+	}
+	output, err := m.eth.CallContract(ctx, ethereum.CallMsg{To: &m.Address, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	values, err := parsedABI.Unpack("aggregate3", output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack aggregate3: %v", err)
+	}
+	var returned []struct {
+		Success    bool
+		ReturnData []byte
+	}
+	if err = parsedABI.Methods["aggregate3"].Outputs.Copy(&returned, values); err != nil {
+		return nil, fmt.Errorf("failed to decode aggregate3 results: %v", err)
+	}
+	if len(returned) != len(calls) {
+		return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(returned), len(calls))
+	}
+	results := make([]Result, len(returned))
+	for i, item := range returned {
+		results[i] = Result(item)
+	}
+	return results, nil
+}
+
+// CallContracts 按 ABI 编码子调用，执行 aggregate3 后解码返回值
+func (m *Multicall) CallContracts(ctx context.Context, calls []ContractCall) ([]CallResult, error) {
+	raw := make([]Call, len(calls))
+	for i, call := range calls {
+		data, err := call.ABI.Pack(call.Method, call.Args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack %s: %v", call.Method, err)
+		}
+		raw[i] = Call{Target: call.Target, AllowFailure: call.AllowFailure, CallData: data}
+	}
+	returned, err := m.Aggregate3(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]CallResult, len(calls))
+	for i, call := range calls {
+		results[i].Success = returned[i].Success
+		if !returned[i].Success {
+			results[i].Err = fmt.Errorf("%w: %s on %s", ErrCallFailed, call.Method, call.Target.Hex())
+			continue
+		}
+		results[i].Values, results[i].Err = call.ABI.Unpack(call.Method, returned[i].ReturnData)
+	}
+	return results, nil
+}
@@ -0,0 +1,112 @@
+package multicall
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+)
+
+// newMulticallServer 模拟 Multicall3：usdt 精度为 6，每个地址余额为 12.5，broken 的所有调用都失败
+func newMulticallServer(t *testing.T, broken common.Address) *httptest.Server {
+	t.Helper()
+	erc20ABI, _ := erc20.Erc20MetaData.GetAbi()
+	return rpctest.NewServer(t, rpctest.Handlers{
+		"eth_call": func(params []json.RawMessage) (interface{}, error) {
+			input := rpctest.ParseCall(params).Calldata()
+			if len(input) < 4 {
+				return nil, rpctest.ErrReverted
+			}
+			values, err := parsedABI.Methods["aggregate3"].Inputs.Unpack(input[4:])
+			if err != nil {
+				return nil, err
+			}
+			var calls []Call
+			_ = parsedABI.Methods["aggregate3"].Inputs.Copy(&calls, values)
+			type result struct {
+				Success    bool
+				ReturnData []byte
+			}
+			results := make([]result, len(calls))
+			for i, call := range calls {
+				if call.Target == broken {
+					continue
+				}
+				method, _ := erc20ABI.MethodById(call.CallData[:4])
+				var data []byte
+				switch method.Name {
+				case "decimals":
+					data, _ = method.Outputs.Pack(uint8(6))
+				case "balanceOf":
+					data, _ = method.Outputs.Pack(big.NewInt(12500000))
+				}
+				results[i] = result{Success: true, ReturnData: data}
+			}
+			output, _ := parsedABI.Methods["aggregate3"].Outputs.Pack(results)
+			return hexutil.Bytes(output), nil
+		},
+	})
+}
+
+func TestMulticall_ERC20Balances(t *testing.T) {
+	usdt := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	broken := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	holders := []common.Address{
+		common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768"),
+		common.HexToAddress("0xE837C72A310F201AD2E3CA4E44C1DD0D41F4EC8B"),
+	}
+	server := newMulticallServer(t, broken)
+	eth := eth_helper.NewEthHelper(server.URL)
+	defer eth.Close()
+	m := NewMulticall(eth)
+	m.MaxCalls = 2
+
+	balances, err := m.ERC20Balances(context.Background(), []common.Address{usdt, broken}, holders)
+	if err != nil {
+		t.Fatalf("ERC20Balances() error = %v", err)
+	}
+	if len(balances) != 4 {
+		t.Fatalf("ERC20Balances() returned %d results, want 4", len(balances))
+	}
+	for _, item := range balances {
+		if item.Token == broken {
+			if item.Err == nil {
+				t.Errorf("ERC20Balances() %s/%s error = nil, want error", item.Token, item.Holder)
+			}
+			continue
+		}
+		if item.Err != nil || item.Balance.String() != "12.5" {
+			t.Errorf("ERC20Balances() %s/%s = %v, %v, want 12.5", item.Token, item.Holder, item.Balance, item.Err)
+		}
+	}
+}
+
+func TestStringValue(t *testing.T) {
+	erc20ABI, _ := erc20.Erc20MetaData.GetAbi()
+	packed, _ := erc20ABI.Methods["symbol"].Outputs.Pack("USDT")
+	var bytes32 [32]byte
+	copy(bytes32[:], "MKR")
+	tests := []struct {
+		name   string
+		result Result
+		want   string
+	}{
+		{"string", Result{Success: true, ReturnData: packed}, "USDT"},
+		{"bytes32", Result{Success: true, ReturnData: bytes32[:]}, "MKR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := stringValue(erc20ABI, "symbol", tt.result)
+			if err != nil || got != tt.want {
+				t.Errorf("stringValue() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package eth_helper
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+)
+
+// NonceSource 查询地址在链上 pending 状态下的 nonce
+type NonceSource func(ctx context.Context, address common.Address) (uint64, error)
+
+// NonceManager 为每个地址顺序分配 nonce，保证并发发送交易时 nonce 不冲突
+type NonceManager struct {
+	source NonceSource
+	store  eth_interface.NonceStoreInterface
+
+	mu       sync.Mutex
+	accounts map[common.Address]*nonceAccount
+}
+
+// nonceAccount 单个地址的 nonce 状态
+type nonceAccount struct {
+	mu       sync.Mutex
+	synced   bool
+	next     uint64              // 下一个尚未分配的 nonce
+	released []uint64            // 已分配但发送失败、需要优先复用的 nonce，升序
+	inflight map[uint64]struct{} // 已分配、尚未确认广播成功也没有归还的 nonce
+}
+
+// NewNonceManager 创建 nonce 管理器，store 为 nil 时只在内存中保存
+// store 只保存下一个 nonce，归还的 nonce 不会持久化；进程重启前存在未复用的 nonce 时，
+// 重启后 next 会高于链上 pending nonce，需要调用 Resync 重新同步
+func NewNonceManager(source NonceSource, store eth_interface.NonceStoreInterface) *NonceManager {
+	return &NonceManager{
+		source:   source,
+		store:    store,
+		accounts: make(map[common.Address]*nonceAccount),
+	}
+}
+
+func (m *NonceManager) account(address common.Address) *nonceAccount {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	acc, ok := m.accounts[address]
+	if !ok {
+		acc = &nonceAccount{}
+		m.accounts[address] = acc
+	}
+	return acc
+}
+
+// Next 分配地址的下一个 nonce，优先复用之前释放的 nonce
+func (m *NonceManager) Next(ctx context.Context, address common.Address) (uint64, error) {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	if !acc.synced {
+		if err := m.sync(ctx, address, acc); err != nil {
+			return 0, err
+		}
+	}
+	if len(acc.released) > 0 {
+		nonce := acc.released[0]
+		acc.released = acc.released[1:]
+		acc.inflight[nonce] = struct{}{}
+		return nonce, nil
+	}
+	nonce := acc.next
+	acc.next++
+	if err := m.save(address, acc.next); err != nil {
+		acc.next--
+		return 0, err
+	}
+	acc.inflight[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// sent 标记 nonce 的交易已经被节点接受，之后节点返回的 pending nonce 会包含它
+func (m *NonceManager) sent(address common.Address, nonce uint64) {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	delete(acc.inflight, nonce)
+}
+
+// allocated 判断 nonce 是否由 NonceManager 分配且尚未广播或归还
+func (m *NonceManager) allocated(address common.Address, nonce uint64) bool {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	_, ok := acc.inflight[nonce]
+	return ok
+}
+
+// Release 归还一个已分配但没有成功广播的 nonce，避免出现 nonce 空洞
+func (m *NonceManager) Release(address common.Address, nonce uint64) error {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	if !acc.synced || nonce >= acc.next {
+		return nil
+	}
+	delete(acc.inflight, nonce)
+	idx := sort.Search(len(acc.released), func(i int) bool { return acc.released[i] >= nonce })
+	if idx < len(acc.released) && acc.released[idx] == nonce {
+		return nil
+	}
+	acc.released = append(acc.released, 0)
+	copy(acc.released[idx+1:], acc.released[idx:])
+	acc.released[idx] = nonce
+	// 末尾连续释放的 nonce 直接回退 next
+	for len(acc.released) > 0 && acc.released[len(acc.released)-1] == acc.next-1 {
+		acc.released = acc.released[:len(acc.released)-1]
+		acc.next--
+	}
+	return m.save(address, acc.next)
+}
+
+// Resync 以链上 pending nonce 为准重新同步
+// 低于链上 nonce 的本地记录全部丢弃；仍有已分配、尚未广播的 nonce 时 next 只前移不回退，避免重复分配
+func (m *NonceManager) Resync(ctx context.Context, address common.Address) error {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	nonce, err := m.source(ctx, address)
+	if err != nil {
+		return err
+	}
+	if !acc.synced {
+		acc.next, acc.released, acc.inflight, acc.synced = nonce, nil, make(map[uint64]struct{}), true
+		return m.save(address, acc.next)
+	}
+	for n := range acc.inflight {
+		if n < nonce {
+			delete(acc.inflight, n)
+		}
+	}
+	if len(acc.inflight) == 0 || nonce > acc.next {
+		acc.next = nonce
+	}
+	released := acc.released[:0]
+	for _, n := range acc.released {
+		if n >= nonce && n < acc.next {
+			released = append(released, n)
+		}
+	}
+	acc.released = released
+	return m.save(address, acc.next)
+}
+
+// sync 首次使用地址时，取持久化记录和链上 pending nonce 中较大的一个
+func (m *NonceManager) sync(ctx context.Context, address common.Address, acc *nonceAccount) error {
+	nonce, err := m.source(ctx, address)
+	if err != nil {
+		return err
+	}
+	if m.store != nil {
+		stored, ok, err := m.store.LoadNonce(address)
+		if err != nil {
+			return err
+		}
+		if ok && stored > nonce {
+			nonce = stored
+		}
+	}
+	acc.next = nonce
+	acc.released = nil
+	acc.inflight = make(map[uint64]struct{})
+	acc.synced = true
+	return nil
+}
+
+func (m *NonceManager) save(address common.Address, next uint64) error {
+	if m.store == nil {
+		return nil
+	}
+	return m.store.SaveNonce(address, next)
+}
+
+// SetNonceStore 设置 nonce 的持久化存储，需要在发送第一笔交易之前调用
+func (e *EthHelper) SetNonceStore(store eth_interface.NonceStoreInterface) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.nonceManager = NewNonceManager(e.GetTransactionCount, store)
+}
+
+// NonceManager 返回 EthHelper 使用的 nonce 管理器
+func (e *EthHelper) NonceManager() *NonceManager {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.nonceManager == nil {
+		e.nonceManager = NewNonceManager(e.GetTransactionCount, nil)
+	}
+	return e.nonceManager
+}
+
+// isNonceTooLowError 判断节点是否因为 nonce 已经上链而拒绝交易
+func isNonceTooLowError(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
+
+// isNonceInPoolError 判断交易池中是否已有相同 nonce 的交易，此时 nonce 已被占用但链上 nonce 并未变化
+func isNonceInPoolError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
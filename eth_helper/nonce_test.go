@@ -0,0 +1,169 @@
+package eth_helper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type memoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[common.Address]uint64
+}
+
+func (s *memoryNonceStore) LoadNonce(address common.Address) (uint64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nonce, ok := s.nonces[address]
+	return nonce, ok, nil
+}
+
+func (s *memoryNonceStore) SaveNonce(address common.Address, nonce uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nonces[address] = nonce
+	return nil
+}
+
+func fixedNonceSource(nonce uint64) NonceSource {
+	return func(ctx context.Context, address common.Address) (uint64, error) {
+		return nonce, nil
+	}
+}
+
+func TestNonceManager_Next(t *testing.T) {
+	address := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	m := NewNonceManager(fixedNonceSource(0), nil)
+	for want := uint64(0); want < 3; want++ {
+		got, err := m.Next(context.Background(), address)
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("Next() got = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestNonceManager_Concurrent(t *testing.T) {
+	address := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	m := NewNonceManager(fixedNonceSource(7), nil)
+	const count = 100
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[uint64]bool)
+	)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := m.Next(context.Background(), address)
+			if err != nil {
+				t.Errorf("Next() error = %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[nonce] {
+				t.Errorf("Next() returned duplicate nonce %v", nonce)
+			}
+			seen[nonce] = true
+		}()
+	}
+	wg.Wait()
+	for nonce := uint64(7); nonce < 7+count; nonce++ {
+		if !seen[nonce] {
+			t.Errorf("nonce %v was never handed out", nonce)
+		}
+	}
+}
+
+func TestNonceManager_Release(t *testing.T) {
+	address := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	ctx := context.Background()
+	m := NewNonceManager(fixedNonceSource(10), nil)
+	for i := 0; i < 4; i++ {
+		if _, err := m.Next(ctx, address); err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+	}
+	// 10..13 已分配，归还中间的 11 后应被优先复用
+	if err := m.Release(address, 11); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if got, _ := m.Next(ctx, address); got != 11 {
+		t.Errorf("Next() after Release got = %v, want 11", got)
+	}
+	// 归还最后一个 nonce 时直接回退
+	if err := m.Release(address, 13); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if got, _ := m.Next(ctx, address); got != 13 {
+		t.Errorf("Next() after releasing tail got = %v, want 13", got)
+	}
+	if got, _ := m.Next(ctx, address); got != 14 {
+		t.Errorf("Next() got = %v, want 14", got)
+	}
+}
+
+func TestNonceManager_StoreAndResync(t *testing.T) {
+	address := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	ctx := context.Background()
+	store := &memoryNonceStore{nonces: map[common.Address]uint64{address: 20}}
+	m := NewNonceManager(fixedNonceSource(5), store)
+	if got, _ := m.Next(ctx, address); got != 20 {
+		t.Errorf("Next() with stored nonce got = %v, want 20", got)
+	}
+	if store.nonces[address] != 21 {
+		t.Errorf("stored nonce = %v, want 21", store.nonces[address])
+	}
+	// 20 已分配但还没有广播，Resync 不能回退
+	if err := m.Resync(ctx, address); err != nil {
+		t.Fatalf("Resync() error = %v", err)
+	}
+	if got, _ := m.Next(ctx, address); got != 21 {
+		t.Errorf("Next() after Resync with outstanding nonce got = %v, want 21", got)
+	}
+
+	// 重启后没有未完成的 nonce，Resync 回到链上 pending nonce
+	m = NewNonceManager(fixedNonceSource(5), store)
+	if err := m.Resync(ctx, address); err != nil {
+		t.Fatalf("Resync() error = %v", err)
+	}
+	if got, _ := m.Next(ctx, address); got != 5 {
+		t.Errorf("Next() after restart and Resync got = %v, want 5", got)
+	}
+	m.sent(address, 5)
+	if err := m.Resync(ctx, address); err != nil {
+		t.Fatalf("Resync() error = %v", err)
+	}
+	if got, _ := m.Next(ctx, address); got != 5 {
+		t.Errorf("Next() after Resync with broadcast nonce got = %v, want 5", got)
+	}
+}
+
+func TestIsNonceTooLowError(t *testing.T) {
+	tests := []struct {
+		msg    string
+		tooLow bool
+		inPool bool
+	}{
+		{"nonce too low: address 0x01, tx: 3 state: 5", true, false},
+		{"already known", false, true},
+		{"replacement transaction underpriced", false, true},
+		{"insufficient funds for gas * price + value", false, false},
+	}
+	for _, tt := range tests {
+		err := errors.New(tt.msg)
+		if got := isNonceTooLowError(err); got != tt.tooLow {
+			t.Errorf("isNonceTooLowError(%q) = %v, want %v", tt.msg, got, tt.tooLow)
+		}
+		if got := isNonceInPoolError(err); got != tt.inPool {
+			t.Errorf("isNonceInPoolError(%q) = %v, want %v", tt.msg, got, tt.inPool)
+		}
+	}
+}
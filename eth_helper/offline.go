@@ -0,0 +1,143 @@
+package eth_helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+)
+
+// ErrSignerMismatch 签名器地址与交易的发送地址不一致
+var ErrSignerMismatch = errors.New("signer does not match transaction sender")
+
+// TxParams BuildUnsigned 的参数，除 From 外零值表示使用默认值
+type TxParams struct {
+	From      common.Address  // 发送地址，签名时必须使用该地址的签名器
+	To        *common.Address // 接收地址，nil 表示创建合约
+	Amount    decimal.Decimal // 转账金额，单位 ETH
+	Data      []byte
+	GasLimit  uint64   // 大于估算值时使用 GasLimit，否则使用估算值
+	GasPrice  *big.Int // 传统交易的 gasPrice，EIP-1559 交易的 maxFeePerGas
+	GasTipCap *big.Int // EIP-1559 交易的 maxPriorityFeePerGas，传统交易忽略
+	Nonce     *uint64  // 为 nil 时由 NonceManager 分配
+	TxType    TxType   // 交易类型，TxTypeAuto 表示使用 SetTxType 设置的类型
+
+	AccessList     types.AccessList // 交易携带的访问列表，传统交易会改为 AccessListTx
+	AutoAccessList bool             // AccessList 为空时通过 eth_createAccessList 生成，只在能节省 gas 时使用
+	BlobGasFeeCap  *big.Int         // BuildUnsignedBlob 使用的 maxFeePerBlobGas，为 nil 时使用 SuggestBlobGasFeeCap
+}
+
+// UnsignedTx 所有字段都已确定的未签名交易，序列化后可以交给离线机器签名
+type UnsignedTx struct {
+	From    common.Address
+	ChainID *big.Int
+	Tx      *types.Transaction
+}
+
+type unsignedTxJSON struct {
+	From    common.Address     `json:"from"`
+	ChainID *hexutil.Big       `json:"chainId"`
+	Tx      *types.Transaction `json:"tx"`
+}
+
+// unsignedTxRLP UnsignedTx 的 RLP 结构，Tx 为 EIP-2718 编码的交易
+type unsignedTxRLP struct {
+	From    common.Address
+	ChainID *big.Int
+	Tx      []byte
+}
+
+// MarshalJSON 编码为 {"from", "chainId", "tx"}，tx 与 eth_getTransactionByHash 的格式相同
+func (u *UnsignedTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(unsignedTxJSON{From: u.From, ChainID: (*hexutil.Big)(u.ChainID), Tx: u.Tx})
+}
+
+func (u *UnsignedTx) UnmarshalJSON(input []byte) error {
+	var dec unsignedTxJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.ChainID == nil || dec.Tx == nil {
+		return errors.New("unsigned transaction: missing chainId or tx")
+	}
+	u.From, u.ChainID, u.Tx = dec.From, dec.ChainID.ToInt(), dec.Tx
+	return nil
+}
+
+// MarshalBinary 编码为 RLP(from, chainId, tx)
+func (u *UnsignedTx) MarshalBinary() ([]byte, error) {
+	tx, err := u.Tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(unsignedTxRLP{From: u.From, ChainID: u.ChainID, Tx: tx})
+}
+
+func (u *UnsignedTx) UnmarshalBinary(input []byte) error {
+	var dec unsignedTxRLP
+	if err := rlp.DecodeBytes(input, &dec); err != nil {
+		return err
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(dec.Tx); err != nil {
+		return err
+	}
+	u.From, u.ChainID, u.Tx = dec.From, dec.ChainID, tx
+	return nil
+}
+
+// SigningHash 返回签名器需要签名的哈希，可以在离线机器上与在线机器核对
+func (u *UnsignedTx) SigningHash() common.Hash {
+	return types.LatestSignerForChainID(u.ChainID).Hash(u.Tx)
+}
+
+// BuildUnsigned 在线确定交易类型、gasLimit、手续费、chainId 和 nonce，返回未签名交易
+// nonce 由 NonceManager 分配时，Broadcast 会按发送结果标记或归还 nonce，交易最终没有广播需要调用 NonceManager().Release 归还
+func (e *EthHelper) BuildUnsigned(ctx context.Context, params TxParams) (*UnsignedTx, error) {
+	unsigned, _, err := e.buildTx(ctx, params.From, params.To, params.Amount, params.Data, TxOptions{
+		GasLimit:       params.GasLimit,
+		GasPrice:       params.GasPrice,
+		GasTipCap:      params.GasTipCap,
+		Nonce:          params.Nonce,
+		TxType:         params.TxType,
+		AccessList:     params.AccessList,
+		AutoAccessList: params.AutoAccessList,
+	})
+	return unsigned, err
+}
+
+// SignOffline 签名未签名交易，使用本地签名器时不需要网络
+// 返回的交易可以用 MarshalBinary 编码后交给 Broadcast 发送
+func SignOffline(unsigned *UnsignedTx, signer eth_interface.SignerInterface) (*types.Transaction, error) {
+	if signer.Address() != unsigned.From {
+		return nil, fmt.Errorf("%w: want %s, got %s", ErrSignerMismatch, unsigned.From.Hex(), signer.Address().Hex())
+	}
+	signedTx, err := signer.SignTx(context.Background(), unsigned.Tx, unsigned.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %v", err)
+	}
+	return signedTx, nil
+}
+
+// Broadcast 发送已签名的交易，rawTx 为 EIP-2718 编码（与 eth_sendRawTransaction 的参数相同）
+// 交易的 nonce 由 NonceManager 分配时与 Transact 一样处理已分配的 nonce
+func (e *EthHelper) Broadcast(ctx context.Context, rawTx []byte) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to decode transaction: %v", err)
+	}
+	hash, err := e.SendTransaction(ctx, tx)
+	from, senderErr := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if senderErr == nil && e.NonceManager().allocated(from, tx.Nonce()) {
+		e.reclaimNonce(ctx, from, tx.Nonce(), err)
+	}
+	return hash, err
+}
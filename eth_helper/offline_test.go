@@ -0,0 +1,165 @@
+package eth_helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+	"github.com/web3coderecho/web3_helper/eth_helper/signer"
+)
+
+func newUnsignedTxs(from common.Address) map[string]*UnsignedTx {
+	to := common.HexToAddress("0xE837C72A310F201AD2E3CA4E44C1DD0D41F4EC8B")
+	chainID := big.NewInt(56)
+	return map[string]*UnsignedTx{
+		"legacy": {From: from, ChainID: chainID, Tx: types.NewTx(&types.LegacyTx{
+			Nonce: 7, GasPrice: big.NewInt(3e9), Gas: 21000, To: &to, Value: big.NewInt(1e18),
+		})},
+		"dynamic fee": {From: from, ChainID: chainID, Tx: types.NewTx(&types.DynamicFeeTx{
+			ChainID: chainID, Nonce: 8, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(5e9), Gas: 60000, To: &to, Data: []byte{0xa9, 0x05, 0x9c, 0xbb},
+		})},
+		"create": {From: from, ChainID: chainID, Tx: types.NewTx(&types.DynamicFeeTx{
+			ChainID: chainID, Nonce: 9, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(5e9), Gas: 500000, Data: []byte{0x60, 0x80},
+		})},
+	}
+}
+
+func TestUnsignedTx_Serialization(t *testing.T) {
+	from := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	for name, unsigned := range newUnsignedTxs(from) {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := json.Marshal(unsigned)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			var fromJSON UnsignedTx
+			if err := json.Unmarshal(encoded, &fromJSON); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			raw, err := unsigned.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			var fromRLP UnsignedTx
+			if err := fromRLP.UnmarshalBinary(raw); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			for _, got := range []UnsignedTx{fromJSON, fromRLP} {
+				if got.From != from || got.ChainID.Cmp(unsigned.ChainID) != 0 || got.SigningHash() != unsigned.SigningHash() {
+					t.Errorf("decoded = %+v, want %+v", got, unsigned)
+				}
+			}
+		})
+	}
+}
+
+func TestSignOffline(t *testing.T) {
+	key, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	for name, unsigned := range newUnsignedTxs(key.Address()) {
+		t.Run(name, func(t *testing.T) {
+			signedTx, err := SignOffline(unsigned, key)
+			if err != nil {
+				t.Fatalf("SignOffline() error = %v", err)
+			}
+			sender, err := types.Sender(types.LatestSignerForChainID(unsigned.ChainID), signedTx)
+			if err != nil || sender != key.Address() {
+				t.Errorf("SignOffline() sender = %s, %v, want %s", sender.Hex(), err, key.Address().Hex())
+			}
+		})
+	}
+	unsigned := newUnsignedTxs(common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768"))["legacy"]
+	if _, err := SignOffline(unsigned, key); !errors.Is(err, ErrSignerMismatch) {
+		t.Errorf("SignOffline() error = %v, want ErrSignerMismatch", err)
+	}
+}
+
+func TestEthHelper_Broadcast(t *testing.T) {
+	key, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	signedTx, err := SignOffline(newUnsignedTxs(key.Address())["dynamic fee"], key)
+	if err != nil {
+		t.Fatalf("SignOffline() error = %v", err)
+	}
+	rawTx, _ := signedTx.MarshalBinary()
+	var received string
+	server := rpctest.NewServer(t, rpctest.Handlers{
+		"eth_sendRawTransaction": func(params []json.RawMessage) (interface{}, error) {
+			_ = json.Unmarshal(params[0], &received)
+			return signedTx.Hash(), nil
+		},
+	})
+	eth := NewEthHelper(server.URL)
+	defer eth.Close()
+
+	hash, err := eth.Broadcast(context.Background(), rawTx)
+	if err != nil || hash != signedTx.Hash() {
+		t.Errorf("Broadcast() = %s, %v, want %s", hash.Hex(), err, signedTx.Hash().Hex())
+	}
+	if received != hexutil.Encode(rawTx) {
+		t.Errorf("Broadcast() sent %s, want %s", received, hexutil.Encode(rawTx))
+	}
+	if _, err := eth.Broadcast(context.Background(), []byte{0x02}); err == nil {
+		t.Errorf("Broadcast() with invalid transaction error = nil, want error")
+	}
+}
+
+func TestEthHelper_BroadcastManagedNonce(t *testing.T) {
+	key, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	reject := false
+	server := rpctest.NewServer(t, rpctest.Handlers{
+		"eth_getTransactionCount": rpctest.Result("0x7"),
+		"eth_sendRawTransaction": func(params []json.RawMessage) (interface{}, error) {
+			if reject {
+				return nil, errors.New("insufficient funds for gas * price + value")
+			}
+			return common.Hash{1}, nil
+		},
+	})
+	eth := NewEthHelper(server.URL)
+	defer eth.Close()
+	ctx := context.Background()
+	broadcast := func(nonce uint64) error {
+		to := common.HexToAddress("0xE837C72A310F201AD2E3CA4E44C1DD0D41F4EC8B")
+		unsigned := &UnsignedTx{From: key.Address(), ChainID: big.NewInt(56), Tx: types.NewTx(&types.DynamicFeeTx{
+			ChainID: big.NewInt(56), Nonce: nonce, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(5e9), Gas: 21000, To: &to,
+		})}
+		signedTx, err := SignOffline(unsigned, key)
+		if err != nil {
+			t.Fatalf("SignOffline() error = %v", err)
+		}
+		rawTx, _ := signedTx.MarshalBinary()
+		_, err = eth.Broadcast(ctx, rawTx)
+		return err
+	}
+
+	// 广播成功后 nonce 不再处于已分配状态
+	nonce, _ := eth.NonceManager().Next(ctx, key.Address())
+	if err := broadcast(nonce); err != nil {
+		t.Fatalf("Broadcast() error = %v", err)
+	}
+	if eth.NonceManager().allocated(key.Address(), nonce) {
+		t.Errorf("nonce %d is still allocated after a successful broadcast", nonce)
+	}
+
+	// 节点拒绝时归还 nonce，下次分配到同一个 nonce
+	reject = true
+	nonce, _ = eth.NonceManager().Next(ctx, key.Address())
+	if err := broadcast(nonce); err == nil {
+		t.Fatal("Broadcast() error = nil, want rejection")
+	}
+	// 不是 NonceManager 分配的 nonce 不会被归还
+	if err := broadcast(3); err == nil {
+		t.Fatal("Broadcast() error = nil, want rejection")
+	}
+	if next, _ := eth.NonceManager().Next(ctx, key.Address()); next != nonce {
+		t.Errorf("Next() after rejected broadcast = %d, want %d", next, nonce)
+	}
+	if next, _ := eth.NonceManager().Next(ctx, key.Address()); next != nonce+1 {
+		t.Errorf("Next() = %d, want %d", next, nonce+1)
+	}
+}
@@ -0,0 +1,171 @@
+package eth_helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper/revert"
+	"github.com/web3coderecho/web3_helper/utils"
+)
+
+// ErrTransactionReverted 交易已上链但执行失败
+var ErrTransactionReverted = errors.New("transaction reverted")
+
+// WaitOptions WaitForReceipt 的等待参数
+type WaitOptions struct {
+	Confirmations uint64        // 需要的确认数，交易所在区块算作 1 个确认，0 按 1 处理
+	PollInterval  time.Duration // 轮询间隔，默认 3 秒
+	Timeout       time.Duration // 超时时间，0 表示只受 ctx 控制
+}
+
+// ReceiptResult WaitForReceipt 的返回结果
+type ReceiptResult struct {
+	Receipt       *types.Receipt
+	Confirmations uint64              // 返回时的确认数
+	EffectiveFee  decimal.Decimal     // 实际支付的手续费，单位 ETH
+	RevertReason  string              // 交易失败时解析出的原因
+	Revert        *revert.RevertError // 交易失败时解析出的回滚错误
+}
+
+// WaitForReceipt 等待交易上链并达到指定确认数
+// 等待期间如果交易所在区块被重组，会继续等待交易重新上链
+// 交易执行失败时同时返回结果和 *revert.RevertError，可以用 errors.Is(err, ErrTransactionReverted) 判断
+// 调用方取消 ctx 时原样返回 ctx.Err()
+func (e *EthHelper) WaitForReceipt(ctx context.Context, txHash common.Hash, opts WaitOptions) (*ReceiptResult, error) {
+	parent := ctx
+	if opts.Confirmations == 0 {
+		opts.Confirmations = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 3 * time.Second
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		result, err := e.checkReceipt(ctx, txHash, opts.Confirmations)
+		if err != nil {
+			lastErr = err
+		}
+		if result != nil {
+			if result.Receipt.Status != types.ReceiptStatusSuccessful {
+				return result, result.Revert
+			}
+			return result, nil
+		}
+		select {
+		case <-ctx.Done():
+			if err := parent.Err(); err != nil {
+				return nil, err
+			}
+			if lastErr != nil {
+				return nil, fmt.Errorf("timeout waiting for transaction status %s: %v", txHash.String(), lastErr)
+			}
+			return nil, fmt.Errorf("timeout waiting for transaction status %s: %w", txHash.String(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkReceipt 检查一次交易状态，未上链、被重组或确认数不足时返回 nil
+func (e *EthHelper) checkReceipt(ctx context.Context, txHash common.Hash, confirmations uint64) (*ReceiptResult, error) {
+	receipt, err := e.GetTransactionReceipt(ctx, txHash)
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// 确认回执所在区块仍然在主链上
+	header, err := withClient(ctx, e, func(client *ethclient.Client) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, receipt.BlockNumber)
+	})
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if header.Hash() != receipt.BlockHash {
+		return nil, nil
+	}
+	head, err := e.GetBlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head < receipt.BlockNumber.Uint64() {
+		return nil, nil
+	}
+	confirmed := head - receipt.BlockNumber.Uint64() + 1
+	if confirmed < confirmations {
+		return nil, nil
+	}
+	result := &ReceiptResult{
+		Receipt:       receipt,
+		Confirmations: confirmed,
+		EffectiveFee:  utils.FromEther(effectiveFee(receipt)),
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		result.Revert = e.revertReason(ctx, receipt)
+		result.RevertReason = result.Revert.Reason
+	}
+	return result, nil
+}
+
+// effectiveFee 计算交易实际支付的手续费（Wei），包含 blob 手续费
+func effectiveFee(receipt *types.Receipt) *big.Int {
+	fee := new(big.Int)
+	if receipt.EffectiveGasPrice != nil {
+		fee.Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+	}
+	if receipt.BlobGasPrice != nil {
+		fee.Add(fee, new(big.Int).Mul(receipt.BlobGasPrice, new(big.Int).SetUint64(receipt.BlobGasUsed)))
+	}
+	return fee
+}
+
+// revertReason 在交易所在区块的父区块状态上重放交易，解析失败原因
+// 同一区块中排在前面的交易不会被重放，结果可能与实际执行略有差异
+func (e *EthHelper) revertReason(ctx context.Context, receipt *types.Receipt) *revert.RevertError {
+	cause := fmt.Errorf("%w %s", ErrTransactionReverted, receipt.TxHash.String())
+	tx, _, err := e.GetTransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return &revert.RevertError{Err: cause}
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return &revert.RevertError{Err: cause}
+	}
+	parentBlock := new(big.Int).Sub(receipt.BlockNumber, common.Big1)
+	if parentBlock.Sign() < 0 {
+		parentBlock.SetInt64(0)
+	}
+	_, err = e.CallContract(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, parentBlock)
+	revertErr, ok := e.RevertDecoder().FromError(err)
+	if !ok {
+		// 重放没有回滚，通常是 gas 不足导致的失败
+		return &revert.RevertError{Reason: "transaction failed", Err: cause}
+	}
+	res := *revertErr
+	res.Err = cause
+	return &res
+}
@@ -0,0 +1,135 @@
+package eth_helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+)
+
+// receiptChain 模拟交易在区块 10 上链：第一次轮询时区块 10 已被重组，之后回到包含交易的区块，
+// 每次轮询链头前进一个区块
+type receiptChain struct {
+	mu       sync.Mutex
+	polls    int
+	status   uint64
+	tx       *types.Transaction
+	callArgs []json.RawMessage
+}
+
+func newReceiptServer(t *testing.T, chain *receiptChain) *EthHelper {
+	t.Helper()
+	canonical := &types.Header{Number: big.NewInt(10), Difficulty: common.Big0}
+	reorged := &types.Header{Number: big.NewInt(10), Difficulty: common.Big0, Extra: []byte("reorged")}
+	server := rpctest.NewServer(t, rpctest.Handlers{
+		"eth_getTransactionReceipt": func(params []json.RawMessage) (interface{}, error) {
+			chain.mu.Lock()
+			defer chain.mu.Unlock()
+			chain.polls++
+			return &types.Receipt{
+				Status:            chain.status,
+				TxHash:            chain.tx.Hash(),
+				BlockHash:         canonical.Hash(),
+				BlockNumber:       canonical.Number,
+				GasUsed:           21000,
+				EffectiveGasPrice: big.NewInt(1e9),
+				Logs:              []*types.Log{},
+			}, nil
+		},
+		"eth_getBlockByNumber": func(params []json.RawMessage) (interface{}, error) {
+			chain.mu.Lock()
+			defer chain.mu.Unlock()
+			if chain.polls == 1 {
+				return reorged, nil
+			}
+			return canonical, nil
+		},
+		"eth_blockNumber": func(params []json.RawMessage) (interface{}, error) {
+			chain.mu.Lock()
+			defer chain.mu.Unlock()
+			return hexutil.Uint64(8 + chain.polls), nil
+		},
+		"eth_getTransactionByHash": rpctest.Result(chain.tx),
+		"eth_call": func(params []json.RawMessage) (interface{}, error) {
+			chain.mu.Lock()
+			chain.callArgs = params
+			chain.mu.Unlock()
+			return nil, rpctest.ErrReverted
+		},
+	})
+	eth := NewEthHelper(server.URL)
+	t.Cleanup(eth.Close)
+	return eth
+}
+
+func signedTestTx(t *testing.T) *types.Transaction {
+	t.Helper()
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to})
+	if err != nil {
+		t.Fatalf("SignNewTx() error = %v", err)
+	}
+	return tx
+}
+
+func TestEthHelper_WaitForReceipt(t *testing.T) {
+	chain := &receiptChain{status: types.ReceiptStatusSuccessful, tx: signedTestTx(t)}
+	eth := newReceiptServer(t, chain)
+
+	// 第 1 次轮询区块被重组，第 2 次只有 1 个确认，第 3 次达到 2 个确认
+	result, err := eth.WaitForReceipt(context.Background(), chain.tx.Hash(), WaitOptions{Confirmations: 2, PollInterval: 10 * time.Millisecond, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("WaitForReceipt() error = %v", err)
+	}
+	if chain.polls != 3 || result.Confirmations != 2 {
+		t.Errorf("WaitForReceipt() returned after %d polls with %d confirmations, want 3 polls and 2 confirmations", chain.polls, result.Confirmations)
+	}
+	if result.EffectiveFee.String() != "0.000021" {
+		t.Errorf("WaitForReceipt() effective fee = %s, want 0.000021", result.EffectiveFee)
+	}
+}
+
+func TestEthHelper_WaitForReceiptReverted(t *testing.T) {
+	chain := &receiptChain{status: types.ReceiptStatusFailed, tx: signedTestTx(t), polls: 1}
+	eth := newReceiptServer(t, chain)
+
+	result, err := eth.WaitForReceipt(context.Background(), chain.tx.Hash(), WaitOptions{PollInterval: 10 * time.Millisecond, Timeout: 5 * time.Second})
+	if !errors.Is(err, ErrTransactionReverted) || result == nil || result.Revert == nil {
+		t.Fatalf("WaitForReceipt() = %v, %v, want ErrTransactionReverted", result, err)
+	}
+	// 在父区块的状态上重放
+	var block string
+	if len(chain.callArgs) < 2 || json.Unmarshal(chain.callArgs[1], &block) != nil || block != "0x9" {
+		t.Errorf("revert replayed at block %s, want 0x9", block)
+	}
+}
+
+func TestEthHelper_WaitForReceiptCanceled(t *testing.T) {
+	server := rpctest.NewServer(t, rpctest.Handlers{
+		"eth_getTransactionReceipt": rpctest.Result(nil),
+	})
+	eth := NewEthHelper(server.URL)
+	defer eth.Close()
+	hash := common.HexToHash("0x01")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(30*time.Millisecond, cancel)
+	if _, err := eth.WaitForReceipt(ctx, hash, WaitOptions{PollInterval: 10 * time.Millisecond, Timeout: 5 * time.Second}); err != context.Canceled {
+		t.Errorf("WaitForReceipt() after cancel error = %v, want context.Canceled", err)
+	}
+	_, err := eth.WaitForReceipt(context.Background(), hash, WaitOptions{PollInterval: 10 * time.Millisecond, Timeout: 30 * time.Millisecond})
+	if err == nil || !strings.HasPrefix(err.Error(), "timeout waiting for transaction status") {
+		t.Errorf("WaitForReceipt() after timeout error = %v, want timeout waiting for transaction status", err)
+	}
+}
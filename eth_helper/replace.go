@@ -0,0 +1,268 @@
+package eth_helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+)
+
+// DefaultReplacementPriceBump 替换交易时手续费的默认最小涨幅（百分比），与 geth txpool 默认的 pricebump 一致
+const DefaultReplacementPriceBump = 10
+
+// blobReplacementPriceBump 替换 blob 交易时手续费的最小涨幅（百分比），geth blobpool 要求翻倍
+const blobReplacementPriceBump = 100
+
+// maxTrackedSidecars 最多缓存最近发送的 blob 交易 sidecar 数量
+const maxTrackedSidecars = 16
+
+var (
+	// ErrTransactionMined 交易已经上链，无法再替换
+	ErrTransactionMined = errors.New("transaction already mined")
+	// ErrUnsupportedReplacement 交易类型不支持替换
+	ErrUnsupportedReplacement = errors.New("unsupported transaction type for replacement")
+	// ErrMissingBlobSidecar 节点返回的 blob 交易不带 sidecar，本地也没有缓存，无法替换
+	ErrMissingBlobSidecar = errors.New("blob sidecar of transaction not found")
+	// ErrInvalidFeeFactor 手续费倍数不是有限的正数
+	ErrInvalidFeeFactor = errors.New("fee factor must be a finite positive number")
+)
+
+// replacementTracker 记录交易的替换关系
+type replacementTracker struct {
+	mu         sync.Mutex
+	replacedBy map[common.Hash]common.Hash // 原交易 -> 替换它的交易
+	replaces   map[common.Hash]common.Hash // 替换交易 -> 被替换的原交易
+	sidecars   map[common.Hash]*types.BlobTxSidecar
+	sidecarLog []common.Hash // sidecars 的写入顺序，超过 maxTrackedSidecars 时淘汰最早的
+}
+
+// addSidecar 缓存已发送 blob 交易的 sidecar，替换时节点无法返回 sidecar
+func (r *replacementTracker) addSidecar(hash common.Hash, sidecar *types.BlobTxSidecar) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sidecars == nil {
+		r.sidecars = make(map[common.Hash]*types.BlobTxSidecar)
+	}
+	if _, ok := r.sidecars[hash]; ok {
+		return
+	}
+	if len(r.sidecarLog) >= maxTrackedSidecars {
+		delete(r.sidecars, r.sidecarLog[0])
+		r.sidecarLog = r.sidecarLog[1:]
+	}
+	r.sidecars[hash] = sidecar
+	r.sidecarLog = append(r.sidecarLog, hash)
+}
+
+func (r *replacementTracker) sidecar(hash common.Hash) *types.BlobTxSidecar {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sidecars[hash]
+}
+
+func (r *replacementTracker) add(oldHash, newHash common.Hash) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.replacedBy == nil {
+		r.replacedBy = make(map[common.Hash]common.Hash)
+		r.replaces = make(map[common.Hash]common.Hash)
+	}
+	r.replacedBy[oldHash] = newHash
+	r.replaces[newHash] = oldHash
+}
+
+// chain 返回 hash 所在替换链上的全部交易，按发送顺序排列
+func (r *replacementTracker) chain(hash common.Hash) []common.Hash {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	root := hash
+	for {
+		prev, ok := r.replaces[root]
+		if !ok {
+			break
+		}
+		root = prev
+	}
+	hashes := []common.Hash{root}
+	for {
+		next, ok := r.replacedBy[hashes[len(hashes)-1]]
+		if !ok {
+			break
+		}
+		hashes = append(hashes, next)
+	}
+	return hashes
+}
+
+// SetReplacementPriceBump 设置替换交易时手续费的最小涨幅（百分比），需要与节点的 txpool.pricebump 一致
+// percent 小于等于 0 时使用 DefaultReplacementPriceBump
+func (e *EthHelper) SetReplacementPriceBump(percent int64) {
+	e.priceBump = percent
+}
+
+// replacementPriceBump 返回替换交易时手续费的最小涨幅
+func (e *EthHelper) replacementPriceBump() int64 {
+	if e.priceBump <= 0 {
+		return DefaultReplacementPriceBump
+	}
+	return e.priceBump
+}
+
+// ReplacementChain 返回 txHash 所在替换链上的全部交易哈希，第一个为原始交易，最后一个为最新的替换交易
+func (e *EthHelper) ReplacementChain(txHash common.Hash) []common.Hash {
+	return e.replacements.chain(txHash)
+}
+
+// MinedReplacement 在替换链中查找最终上链的交易，都未上链时返回 ethereum.NotFound
+func (e *EthHelper) MinedReplacement(ctx context.Context, txHash common.Hash) (common.Hash, *types.Receipt, error) {
+	for _, hash := range e.ReplacementChain(txHash) {
+		receipt, err := e.GetTransactionReceipt(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return common.Hash{}, nil, err
+		}
+		return hash, receipt, nil
+	}
+	return common.Hash{}, nil, ethereum.NotFound
+}
+
+// SpeedUp 以相同 nonce 和提高后的手续费重新发送交易，factor 为手续费倍数
+// 实际涨幅不低于 SetReplacementPriceBump 设置的涨幅，blob 交易不低于 100%，返回替换交易的哈希
+// blob 交易只能替换本实例最近发送的，节点不返回 sidecar，需要使用本地缓存的 sidecar
+// factor 不是有限的正数时返回 ErrInvalidFeeFactor，不大于 1 时按最小涨幅替换
+func (e *EthHelper) SpeedUp(ctx context.Context, txHash common.Hash, factor float64, signer eth_interface.SignerInterface) (common.Hash, error) {
+	if !(factor > 0) || math.IsInf(factor, 1) {
+		return common.Hash{}, fmt.Errorf("%w: %v", ErrInvalidFeeFactor, factor)
+	}
+	return e.replace(ctx, txHash, factor, signer, false)
+}
+
+// Cancel 以相同 nonce 发送一笔金额为 0 的自转账，替换掉尚未上链的交易
+// blob 交易只能被 blob 交易替换，取消时自转账仍然携带原交易的 blob
+func (e *EthHelper) Cancel(ctx context.Context, txHash common.Hash, signer eth_interface.SignerInterface) (common.Hash, error) {
+	return e.replace(ctx, txHash, 1, signer, true)
+}
+
+func (e *EthHelper) replace(ctx context.Context, txHash common.Hash, factor float64, signer eth_interface.SignerInterface, cancel bool) (common.Hash, error) {
+	// 总是基于替换链上最新的交易进行替换
+	chain := e.ReplacementChain(txHash)
+	latest := chain[len(chain)-1]
+	tx, pending, err := e.GetTransactionByHash(ctx, latest)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to load transaction %s: %v", latest.String(), err)
+	}
+	if !pending {
+		return common.Hash{}, ErrTransactionMined
+	}
+	chainID, err := e.GetChainId(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to recover sender: %v", err)
+	}
+	if signer.Address() != from {
+		return common.Hash{}, fmt.Errorf("signer does not match sender %s", from.String())
+	}
+	to, value, data, gas := tx.To(), tx.Value(), tx.Data(), tx.Gas()
+	if cancel {
+		to, value, data, gas = &from, common.Big0, nil, 21000
+	}
+	var txData types.TxData
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		gasPrice := bumpFee(tx.GasPrice(), factor, e.replacementPriceBump())
+		if current, err := e.GetGasPrice(ctx); err == nil && current.Cmp(gasPrice) > 0 {
+			gasPrice = current
+		}
+		if tx.Type() == types.LegacyTxType {
+			txData = &types.LegacyTx{Nonce: tx.Nonce(), GasPrice: gasPrice, Gas: gas, To: to, Value: value, Data: data}
+		} else {
+			txData = &types.AccessListTx{ChainID: chainID, Nonce: tx.Nonce(), GasPrice: gasPrice, Gas: gas, To: to, Value: value, Data: data, AccessList: tx.AccessList()}
+		}
+	case types.DynamicFeeTxType:
+		tip, feeCap := e.bumpDynamicFee(ctx, tx, factor, e.replacementPriceBump())
+		txData = &types.DynamicFeeTx{ChainID: chainID, Nonce: tx.Nonce(), GasTipCap: tip, GasFeeCap: feeCap, Gas: gas, To: to, Value: value, Data: data, AccessList: tx.AccessList()}
+	case types.BlobTxType:
+		sidecar := tx.BlobTxSidecar()
+		if sidecar == nil {
+			sidecar = e.replacements.sidecar(latest)
+		}
+		if sidecar == nil {
+			return common.Hash{}, ErrMissingBlobSidecar
+		}
+		tip, feeCap := e.bumpDynamicFee(ctx, tx, factor, blobReplacementPriceBump)
+		blobFeeCap := bumpFee(tx.BlobGasFeeCap(), factor, blobReplacementPriceBump)
+		if suggested, err := e.SuggestBlobGasFeeCap(ctx); err == nil && suggested.Cmp(blobFeeCap) > 0 {
+			blobFeeCap = suggested
+		}
+		txData = &types.BlobTx{
+			ChainID:    uint256.MustFromBig(chainID),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  uint256.MustFromBig(tip),
+			GasFeeCap:  uint256.MustFromBig(feeCap),
+			Gas:        gas,
+			To:         *to,
+			Value:      uint256.MustFromBig(value),
+			Data:       data,
+			AccessList: tx.AccessList(),
+			BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+			BlobHashes: tx.BlobHashes(),
+			Sidecar:    sidecar,
+		}
+	default:
+		return common.Hash{}, ErrUnsupportedReplacement
+	}
+	signedTx, err := signer.SignTx(ctx, types.NewTx(txData), chainID)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to sign transaction: %v", err)
+	}
+	newHash, err := e.SendTransaction(ctx, signedTx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	e.replacements.add(latest, newHash)
+	return newHash, nil
+}
+
+// bumpDynamicFee 提高 EIP-1559 交易的 tip 和 feeCap，并且不低于节点当前建议的手续费
+func (e *EthHelper) bumpDynamicFee(ctx context.Context, tx *types.Transaction, factor float64, priceBump int64) (tip, feeCap *big.Int) {
+	tip = bumpFee(tx.GasTipCap(), factor, priceBump)
+	feeCap = bumpFee(tx.GasFeeCap(), factor, priceBump)
+	if fee, err := e.SuggestDynamicFee(ctx); err == nil {
+		if fee.GasTipCap.Cmp(tip) > 0 {
+			tip = fee.GasTipCap
+		}
+		if fee.GasFeeCap.Cmp(feeCap) > 0 {
+			feeCap = fee.GasFeeCap
+		}
+	}
+	if tip.Cmp(feeCap) > 0 {
+		feeCap = new(big.Int).Set(tip)
+	}
+	return tip, feeCap
+}
+
+// bumpFee 按倍数提高手续费，结果不低于节点要求的最小替换涨幅 priceBump（百分比）
+func bumpFee(old *big.Int, factor float64, priceBump int64) *big.Int {
+	minimum := new(big.Int).Mul(old, big.NewInt(100+priceBump))
+	minimum.Div(minimum, big.NewInt(100))
+	minimum.Add(minimum, common.Big1)
+	bumped := decimal.NewFromBigInt(old, 0).Mul(decimal.NewFromFloat(factor)).Ceil().BigInt()
+	if bumped.Cmp(minimum) < 0 {
+		return minimum
+	}
+	return bumped
+}
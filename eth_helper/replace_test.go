@@ -0,0 +1,76 @@
+package eth_helper
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBumpFee(t *testing.T) {
+	tests := []struct {
+		name   string
+		old    *big.Int
+		factor float64
+		bump   int64
+		want   *big.Int
+	}{
+		{"factor below minimum bump", big.NewInt(100), 1.05, 10, big.NewInt(111)},
+		{"factor above minimum bump", big.NewInt(100), 1.5, 10, big.NewInt(150)},
+		{"zero fee", big.NewInt(0), 2, 10, big.NewInt(1)},
+		{"rounds up", big.NewInt(3), 1.5, 10, big.NewInt(5)},
+		{"blob transaction doubles", big.NewInt(100), 1.5, 100, big.NewInt(201)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bumpFee(tt.old, tt.factor, tt.bump); got.Cmp(tt.want) != 0 {
+				t.Errorf("bumpFee() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEthHelper_SetReplacementPriceBump(t *testing.T) {
+	e := NewEthHelper("")
+	if got := e.replacementPriceBump(); got != DefaultReplacementPriceBump {
+		t.Errorf("replacementPriceBump() = %d, want %d", got, DefaultReplacementPriceBump)
+	}
+	e.SetReplacementPriceBump(25)
+	if got := bumpFee(big.NewInt(100), 1, e.replacementPriceBump()); got.Cmp(big.NewInt(126)) != 0 {
+		t.Errorf("bumpFee() with 25%% bump = %v, want 126", got)
+	}
+	e.SetReplacementPriceBump(0)
+	if got := e.replacementPriceBump(); got != DefaultReplacementPriceBump {
+		t.Errorf("replacementPriceBump() after reset = %d, want %d", got, DefaultReplacementPriceBump)
+	}
+}
+
+func TestEthHelper_SpeedUpInvalidFactor(t *testing.T) {
+	e := NewEthHelper("")
+	for _, factor := range []float64{0, -1.5, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := e.SpeedUp(context.Background(), common.Hash{1}, factor, nil); !errors.Is(err, ErrInvalidFeeFactor) {
+			t.Errorf("SpeedUp() with factor %v error = %v, want ErrInvalidFeeFactor", factor, err)
+		}
+	}
+}
+
+func TestReplacementChain(t *testing.T) {
+	e := NewEthHelper("")
+	a, b, c := common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")
+	e.replacements.add(a, b)
+	e.replacements.add(b, c)
+	want := []common.Hash{a, b, c}
+	for _, hash := range want {
+		if got := e.ReplacementChain(hash); !reflect.DeepEqual(got, want) {
+			t.Errorf("ReplacementChain(%s) = %v, want %v", hash, got, want)
+		}
+	}
+	other := common.HexToHash("0x04")
+	if got := e.ReplacementChain(other); !reflect.DeepEqual(got, []common.Hash{other}) {
+		t.Errorf("ReplacementChain(%s) = %v, want only itself", other, got)
+	}
+}
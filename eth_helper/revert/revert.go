@@ -0,0 +1,210 @@
+package revert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc1155"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc721"
+)
+
+// Kind 回滚数据的类型
+type Kind int
+
+const (
+	// KindUnknown 没有回滚数据或无法识别
+	KindUnknown Kind = iota
+	// KindError require/revert 抛出的 Error(string)
+	KindError
+	// KindPanic assert、溢出等抛出的 Panic(uint256)
+	KindPanic
+	// KindCustom ABI 中定义的自定义错误
+	KindCustom
+)
+
+var (
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// panicReasons Solidity Panic(uint256) 错误码的含义
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// RevertError 解析后的合约回滚错误
+type RevertError struct {
+	Kind      Kind
+	Name      string                 // 错误名称，如 Error、Panic、ERC20InsufficientBalance
+	Reason    string                 // 人类可读的原因
+	Args      map[string]interface{} // 自定义错误的参数，未命名的参数为 arg0、arg1…
+	PanicCode *big.Int               // Panic 错误码
+	Data      []byte                 // 原始回滚数据
+	Err       error                  // 原始错误
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + e.Reason
+}
+
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder 根据已知 ABI 解析回滚数据，默认包含内置 ERC20/ERC721/ERC1155 绑定中的自定义错误
+type Decoder struct {
+	mu     sync.RWMutex
+	errors map[[4]byte]abi.Error
+}
+
+// NewDecoder 创建包含内置 ABI 的 Decoder
+func NewDecoder() *Decoder {
+	d := &Decoder{errors: make(map[[4]byte]abi.Error)}
+	for _, metaData := range []*bind.MetaData{erc20.Erc20MetaData, erc721.Erc721MetaData, erc1155.Erc1155MetaData} {
+		if parsed, err := metaData.GetAbi(); err == nil {
+			d.Register(parsed)
+		}
+	}
+	return d
+}
+
+// Register 注册 ABI 中定义的自定义错误
+func (d *Decoder) Register(contractABI *abi.ABI) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, item := range contractABI.Errors {
+		var selector [4]byte
+		copy(selector[:], item.ID[:4])
+		d.errors[selector] = item
+	}
+}
+
+// RegisterJSON 解析 JSON ABI 并注册其中的自定义错误
+func (d *Decoder) RegisterJSON(abiJSON string) error {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return err
+	}
+	d.Register(&parsed)
+	return nil
+}
+
+// Decode 解析回滚数据
+func (d *Decoder) Decode(data []byte) *RevertError {
+	res := &RevertError{Kind: KindUnknown, Data: data}
+	if len(data) < 4 {
+		return res
+	}
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			res.Kind, res.Name, res.Reason = KindError, "Error", reason
+		}
+		return res
+	case bytes.Equal(data[:4], panicSelector):
+		if len(data) == 36 {
+			code := new(big.Int).SetBytes(data[4:])
+			res.Kind, res.Name, res.PanicCode = KindPanic, "Panic", code
+			reason, ok := panicReasons[code.Uint64()]
+			if !ok || !code.IsUint64() {
+				reason = "unknown panic code"
+			}
+			res.Reason = fmt.Sprintf("panic: %s (0x%x)", reason, code)
+		}
+		return res
+	}
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	d.mu.RLock()
+	item, ok := d.errors[selector]
+	d.mu.RUnlock()
+	if !ok {
+		res.Reason = "unknown custom error " + hexutil.Encode(data[:4])
+		return res
+	}
+	values, err := item.Inputs.Unpack(data[4:])
+	if err != nil {
+		res.Reason = "malformed custom error " + item.Name
+		return res
+	}
+	args := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		args[argName(item.Inputs[i], i)] = value
+	}
+	res.Kind, res.Name, res.Args = KindCustom, item.Name, args
+	res.Reason = formatCustom(item, values)
+	return res
+}
+
+// FromError 从 RPC 错误中提取回滚数据并解析，错误与回滚无关时返回 false
+func (d *Decoder) FromError(err error) (*RevertError, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var revertErr *RevertError
+	if errors.As(err, &revertErr) {
+		return revertErr, true
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if raw, decodeErr := hexutil.Decode(data); decodeErr == nil && len(raw) > 0 {
+				res := d.Decode(raw)
+				res.Err = err
+				return res, true
+			}
+		}
+	}
+	if strings.Contains(strings.ToLower(err.Error()), "execution reverted") {
+		res := &RevertError{Kind: KindUnknown, Err: err}
+		// 节点没有返回回滚数据时保留节点给出的原因
+		if _, reason, found := strings.Cut(err.Error(), "execution reverted: "); found {
+			res.Kind, res.Name, res.Reason = KindError, "Error", reason
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+// formatCustom 按 ABI 参数顺序格式化自定义错误，如 ERC20InsufficientBalance(sender=0x..., balance=1, needed=2)
+func formatCustom(item abi.Error, values []interface{}) string {
+	parts := make([]string, 0, len(values))
+	for i, value := range values {
+		if address, ok := value.(common.Address); ok {
+			value = address.Hex()
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v", argName(item.Inputs[i], i), value))
+	}
+	return fmt.Sprintf("%s(%s)", item.Name, strings.Join(parts, ", "))
+}
+
+// argName 返回参数名，未命名的参数按位置命名为 arg0、arg1…
+func argName(input abi.Argument, i int) string {
+	if input.Name == "" {
+		return fmt.Sprintf("arg%d", i)
+	}
+	return input.Name
+}
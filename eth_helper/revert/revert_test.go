@@ -0,0 +1,86 @@
+package revert
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+)
+
+// dataError 模拟节点返回的带回滚数据的 RPC 错误
+type dataError struct {
+	data string
+}
+
+func (e dataError) Error() string          { return "execution reverted" }
+func (e dataError) ErrorData() interface{} { return e.data }
+
+var _ rpc.DataError = dataError{}
+
+func TestDecoder_Decode(t *testing.T) {
+	stringType, _ := abi.NewType("string", "", nil)
+	errorData, _ := abi.Arguments{{Type: stringType}}.Pack("insufficient allowance")
+	errorData = append(append([]byte{}, errorSelector...), errorData...)
+	panicData := append(append([]byte{}, panicSelector...), common.LeftPadBytes([]byte{0x11}, 32)...)
+	erc20ABI, _ := erc20.Erc20MetaData.GetAbi()
+	sender := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	customErr := erc20ABI.Errors["ERC20InsufficientBalance"]
+	customData, _ := customErr.Inputs.Pack(sender, big.NewInt(1), big.NewInt(2))
+	customData = append(customErr.ID[:4:4], customData...)
+	// 参数未命名的自定义错误按位置命名
+	unnamedABI, _ := abi.JSON(strings.NewReader(`[{"type":"error","name":"Unauthorized","inputs":[{"name":"","type":"address"},{"name":"","type":"uint256"}]}]`))
+	unnamedErr := unnamedABI.Errors["Unauthorized"]
+	unnamedData, _ := unnamedErr.Inputs.Pack(sender, big.NewInt(7))
+	unnamedData = append(unnamedErr.ID[:4:4], unnamedData...)
+
+	tests := []struct {
+		name       string
+		data       []byte
+		wantKind   Kind
+		wantReason string
+	}{
+		{"error", errorData, KindError, "insufficient allowance"},
+		{"panic", panicData, KindPanic, "panic: arithmetic overflow or underflow (0x11)"},
+		{"custom", customData, KindCustom, "ERC20InsufficientBalance(sender=" + sender.Hex() + ", balance=1, needed=2)"},
+		{"unnamed custom", unnamedData, KindCustom, "Unauthorized(arg0=" + sender.Hex() + ", arg1=7)"},
+		{"unknown", []byte{0xde, 0xad, 0xbe, 0xef}, KindUnknown, "unknown custom error 0xdeadbeef"},
+		{"empty", nil, KindUnknown, ""},
+	}
+	d := NewDecoder()
+	d.Register(&unnamedABI)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.Decode(tt.data)
+			if got.Kind != tt.wantKind || got.Reason != tt.wantReason {
+				t.Errorf("Decode() = %v, %q, want %v, %q", got.Kind, got.Reason, tt.wantKind, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestDecoder_FromError(t *testing.T) {
+	d := NewDecoder()
+	panicData := append(append([]byte{}, panicSelector...), common.LeftPadBytes([]byte{0x12}, 32)...)
+	rpcErr := dataError{data: hexutil.Encode(panicData)}
+
+	got, ok := d.FromError(rpcErr)
+	if !ok || got.Kind != KindPanic || got.PanicCode.Int64() != 0x12 {
+		t.Fatalf("FromError() = %+v, %v, want panic 0x12", got, ok)
+	}
+	if !errors.Is(got, rpcErr) {
+		t.Errorf("FromError() does not wrap the original error")
+	}
+	if _, ok = d.FromError(errors.New("connection refused")); ok {
+		t.Errorf("FromError() matched a non-revert error")
+	}
+	got, ok = d.FromError(errors.New("execution reverted: Ownable: caller is not the owner"))
+	if !ok || got.Reason != "Ownable: caller is not the owner" {
+		t.Errorf("FromError() = %+v, %v, want reason from message", got, ok)
+	}
+}
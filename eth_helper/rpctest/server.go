@@ -0,0 +1,155 @@
+// Package rpctest 提供离线测试使用的 JSON-RPC 节点桩，按方法名把请求分发给 Handler
+package rpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrReverted 没有回滚数据的 execution reverted，与 geth 调用回滚时返回的错误相同
+var ErrReverted = &Error{Code: 3, Message: "execution reverted", Data: "0x"}
+
+// Error JSON-RPC 错误响应
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// MethodNotFound 节点不支持方法时返回的 -32601 错误
+func MethodNotFound(method string) *Error {
+	return &Error{Code: -32601, Message: "the method " + method + " does not exist/is not available"}
+}
+
+// Handler 处理一个 JSON-RPC 请求，返回值按 JSON 编码为 result
+// 返回 *Error 时原样回复，其他错误回复为 -32000
+type Handler func(params []json.RawMessage) (interface{}, error)
+
+// Handlers 方法名到 Handler 的映射，未注册的方法回复 MethodNotFound
+type Handlers map[string]Handler
+
+// Result 返回固定 result 的 Handler
+func Result(result interface{}) Handler {
+	return func([]json.RawMessage) (interface{}, error) {
+		return result, nil
+	}
+}
+
+// Fail 总是返回 err 的 Handler
+func Fail(err error) Handler {
+	return func([]json.RawMessage) (interface{}, error) {
+		return nil, err
+	}
+}
+
+// CallArgs eth_call、eth_estimateGas 和 eth_createAccessList 的调用参数
+type CallArgs struct {
+	From          *common.Address  `json:"from"`
+	To            *common.Address  `json:"to"`
+	Input         hexutil.Bytes    `json:"input"`
+	Data          hexutil.Bytes    `json:"data"`
+	Value         *hexutil.Big     `json:"value"`
+	AccessList    types.AccessList `json:"accessList"`
+	BlobHashes    []common.Hash    `json:"blobVersionedHashes"`
+	BlobGasFeeCap *hexutil.Big     `json:"maxFeePerBlobGas"`
+}
+
+// Calldata 返回调用数据，ethclient 使用 input 字段，部分调用方使用 data 字段
+func (c CallArgs) Calldata() []byte {
+	if len(c.Input) > 0 {
+		return c.Input
+	}
+	return c.Data
+}
+
+// ParseCall 解码第一个参数中的调用参数
+func ParseCall(params []json.RawMessage) CallArgs {
+	var args CallArgs
+	if len(params) > 0 {
+		_ = json.Unmarshal(params[0], &args)
+	}
+	return args
+}
+
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+func (h Handlers) serve(req request) response {
+	resp := response{JSONRPC: "2.0", ID: req.ID}
+	handler, ok := h[req.Method]
+	if !ok {
+		resp.Error = MethodNotFound(req.Method)
+		return resp
+	}
+	result, err := handler(req.Params)
+	var rpcErr *Error
+	switch {
+	case errors.As(err, &rpcErr):
+		resp.Error = rpcErr
+	case err != nil:
+		resp.Error = &Error{Code: -32000, Message: err.Error()}
+	case result == nil:
+		resp.Result = json.RawMessage("null")
+	default:
+		resp.Result = result
+	}
+	return resp
+}
+
+// NewHandler 创建按 handlers 分发请求的 http.Handler，支持批量请求
+func NewHandler(handlers Handlers) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+			var reqs []request
+			if err := json.Unmarshal(body, &reqs); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			resps := make([]response, len(reqs))
+			for i, req := range reqs {
+				resps[i] = handlers.serve(req)
+			}
+			_ = json.NewEncoder(w).Encode(resps)
+			return
+		}
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(handlers.serve(req))
+	})
+}
+
+// NewServer 启动 NewHandler 的 HTTP 服务，测试结束时自动关闭
+func NewServer(t testing.TB, handlers Handlers) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(NewHandler(handlers))
+	t.Cleanup(server.Close)
+	return server
+}
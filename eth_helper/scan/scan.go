@@ -8,14 +8,24 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/signatures"
 )
 
 type Scan struct {
-	Address   []common.Address `json:"address,omitempty"` // 合约地址（可选）
-	Topics    [][]common.Hash  `json:"topics,omitempty"`  // 日志主题数组（最多4个）
+	Address   []common.Address     `json:"address,omitempty"` // 合约地址（可选）
+	Topics    [][]common.Hash      `json:"topics,omitempty"`  // 日志主题数组（最多4个）
+	Registry  *signatures.Registry `json:"-"`                 // 解析日志使用的签名注册表，nil 表示使用默认注册表
 	ethHelper *eth_helper.EthHelper
 }
 
+// DecodedLog 按签名注册表解析后的日志
+type DecodedLog struct {
+	types.Log
+	Name      string                 // 事件名称，无法识别时为空
+	Signature string                 // 事件签名，如 Transfer(address,address,uint256)
+	Values    map[string]interface{} // 事件参数，包含 indexed 参数
+}
+
 func NewScanFilterQuery(address []common.Address, topics [][]common.Hash, eth *eth_helper.EthHelper) *Scan {
 	return &Scan{
 		Address:   address,
@@ -29,6 +39,31 @@ func (s *Scan) Scan(ctx context.Context, from, to uint64, blockHash string) ([]t
 	return s.ethHelper.FilterLogs(ctx, filterQuery)
 }
 
+// ScanDecoded 查询日志并按签名注册表解析，无法识别的日志只包含原始内容
+func (s *Scan) ScanDecoded(ctx context.Context, from, to uint64, blockHash string) ([]DecodedLog, error) {
+	logs, err := s.Scan(ctx, from, to, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return s.Decode(logs), nil
+}
+
+// Decode 按签名注册表解析日志
+func (s *Scan) Decode(logs []types.Log) []DecodedLog {
+	registry := s.Registry
+	if registry == nil {
+		registry = signatures.Default()
+	}
+	decoded := make([]DecodedLog, len(logs))
+	for i, log := range logs {
+		decoded[i].Log = log
+		if event, values, err := registry.DecodeLog(log); err == nil {
+			decoded[i].Name, decoded[i].Signature, decoded[i].Values = event.RawName, event.Sig, values
+		}
+	}
+	return decoded
+}
+
 func (s *Scan) GetEthFilterQuery(from, to uint64, blockHash string) ethereum.FilterQuery {
 	if blockHash == "" {
 		return ethereum.FilterQuery{
@@ -0,0 +1,28 @@
+package scan
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestScan_Decode(t *testing.T) {
+	owner := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	logs := []types.Log{
+		{
+			Topics: []common.Hash{crypto.Keccak256Hash([]byte("Deposit(address,uint256)")), common.BytesToHash(owner.Bytes())},
+			Data:   common.LeftPadBytes(big.NewInt(1e18).Bytes(), 32),
+		},
+		{Topics: []common.Hash{crypto.Keccak256Hash([]byte("Unknown(uint256)"))}},
+	}
+	decoded := NewScanFilterQuery(nil, nil, nil).Decode(logs)
+	if decoded[0].Signature != "Deposit(address,uint256)" || decoded[0].Values["dst"] != owner || decoded[0].Values["wad"].(*big.Int).Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("Decode() = %+v, want WETH Deposit", decoded[0])
+	}
+	if decoded[1].Name != "" || decoded[1].Values != nil {
+		t.Errorf("Decode() = %+v, want unknown event", decoded[1])
+	}
+}
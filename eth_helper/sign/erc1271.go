@@ -0,0 +1,155 @@
+package sign
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/internal/abiutil"
+	"github.com/web3coderecho/web3_helper/eth_helper/revert"
+)
+
+// ERC1271MagicValue isValidSignature 校验通过时返回的值，即 bytes4(keccak256("isValidSignature(bytes32,bytes)"))
+var ERC1271MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+
+// ERC6492MagicSuffix ERC-6492 包装签名的结尾标记
+var ERC6492MagicSuffix = common.FromHex("0x6492649264926492649264926492649264926492649264926492649264926492")
+
+const erc1271ABI = `[{"inputs":[{"internalType":"bytes32","name":"hash","type":"bytes32"},{"internalType":"bytes","name":"signature","type":"bytes"}],"name":"isValidSignature","outputs":[{"internalType":"bytes4","name":"magicValue","type":"bytes4"}],"stateMutability":"view","type":"function"}]`
+
+// erc6492ValidatorCode 无需部署的 ERC-6492 校验合约，作为创建合约的 eth_call 执行，返回值即调用结果
+// 代码之后依次拼接 factory、signer、len(factoryCalldata)、len(isValidSignature calldata) 四个 32 字节字，以及两段 calldata
+// 先 call factory 部署钱包，成功后 staticcall signer 并原样返回结果，任一调用失败时返回空
+var erc6492ValidatorCode = common.FromHex("0x610047380361004760003960006000604051608060006000515af11561004157600060006060516040516080016020515afa15610041573d600060003e3d6000f35b60006000f3")
+
+var (
+	parsedERC1271ABI = abiutil.MustParseABI(erc1271ABI)
+	erc6492Arguments = abiutil.MustArguments("address", "bytes", "bytes")
+)
+
+// Verifier 同时支持 EOA 和合约钱包的签名校验
+// 先尝试 ecrecover，签名地址是合约时调用 EIP-1271 的 isValidSignature，尚未部署的钱包按 ERC-6492 校验
+type Verifier struct {
+	eth *eth_helper.EthHelper
+}
+
+// NewVerifier 创建签名校验器
+func NewVerifier(eth *eth_helper.EthHelper) *Verifier {
+	return &Verifier{eth: eth}
+}
+
+// VerifyMessage 校验 personal_sign 消息签名
+func (v *Verifier) VerifyMessage(ctx context.Context, signer common.Address, message, signature []byte) (bool, error) {
+	return v.VerifyHash(ctx, signer, common.BytesToHash(accounts.TextHash(message)), signature)
+}
+
+// VerifyTypedData 校验 EIP-712 签名，同时校验 domain 中的 chainId
+func (v *Verifier) VerifyTypedData(ctx context.Context, signer common.Address, typedData apitypes.TypedData, signature []byte) (bool, error) {
+	if err := ValidateChainId(ctx, v.eth, typedData); err != nil {
+		return false, err
+	}
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return false, err
+	}
+	return v.VerifyHash(ctx, signer, hash, signature)
+}
+
+// VerifyHash 校验 hash 的签名是否由 signer 签出，只有查询链上数据失败时返回错误
+func (v *Verifier) VerifyHash(ctx context.Context, signer common.Address, hash common.Hash, signature []byte) (bool, error) {
+	if !bytes.HasSuffix(signature, ERC6492MagicSuffix) {
+		return v.verify(ctx, signer, hash, signature)
+	}
+	factory, factoryCalldata, inner, err := unwrapERC6492(signature)
+	if err != nil {
+		return false, nil
+	}
+	code, err := v.eth.CodeAt(ctx, signer, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code: %v", err)
+	}
+	if len(code) > 0 {
+		// 钱包已经部署，直接校验内层签名
+		return v.verifyContract(ctx, signer, hash, inner)
+	}
+	return v.simulate(ctx, signer, hash, factory, factoryCalldata, inner)
+}
+
+// verify 先尝试 ecrecover，失败且 signer 是合约时调用 isValidSignature
+func (v *Verifier) verify(ctx context.Context, signer common.Address, hash common.Hash, signature []byte) (bool, error) {
+	if recovered, err := RecoverHash(hash.Bytes(), signature); err == nil && recovered == signer {
+		return true, nil
+	}
+	code, err := v.eth.CodeAt(ctx, signer, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code: %v", err)
+	}
+	if len(code) == 0 {
+		return false, nil
+	}
+	return v.verifyContract(ctx, signer, hash, signature)
+}
+
+// verifyContract 调用 EIP-1271 isValidSignature，合约回滚视为签名无效
+func (v *Verifier) verifyContract(ctx context.Context, signer common.Address, hash common.Hash, signature []byte) (bool, error) {
+	data, err := parsedERC1271ABI.Pack("isValidSignature", hash, signature)
+	if err != nil {
+		return false, err
+	}
+	output, err := v.eth.CallContract(ctx, ethereum.CallMsg{To: &signer, Data: data}, nil)
+	if err != nil {
+		var revertErr *revert.RevertError
+		if errors.As(err, &revertErr) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isMagicValue(output), nil
+}
+
+// simulate 通过不带 to 的 eth_call 执行 erc6492ValidatorCode，在同一次调用中先部署钱包再调用 isValidSignature
+// 不依赖 eth_simulateV1，所有节点都支持
+func (v *Verifier) simulate(ctx context.Context, signer common.Address, hash common.Hash, factory common.Address, factoryCalldata, signature []byte) (bool, error) {
+	data, err := parsedERC1271ABI.Pack("isValidSignature", hash, signature)
+	if err != nil {
+		return false, err
+	}
+	code := make([]byte, 0, len(erc6492ValidatorCode)+128+len(factoryCalldata)+len(data))
+	code = append(code, erc6492ValidatorCode...)
+	code = append(code, common.LeftPadBytes(factory.Bytes(), 32)...)
+	code = append(code, common.LeftPadBytes(signer.Bytes(), 32)...)
+	code = append(code, common.LeftPadBytes(big.NewInt(int64(len(factoryCalldata))).Bytes(), 32)...)
+	code = append(code, common.LeftPadBytes(big.NewInt(int64(len(data))).Bytes(), 32)...)
+	code = append(code, factoryCalldata...)
+	code = append(code, data...)
+	output, err := v.eth.CallContract(ctx, ethereum.CallMsg{Data: code}, nil)
+	if err != nil {
+		var revertErr *revert.RevertError
+		if errors.As(err, &revertErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to simulate ERC-6492 deployment: %v", err)
+	}
+	return isMagicValue(output), nil
+}
+
+// unwrapERC6492 解析 abi.encode(factory, factoryCalldata, signature) ++ magicSuffix
+func unwrapERC6492(signature []byte) (common.Address, []byte, []byte, error) {
+	values, err := erc6492Arguments.Unpack(signature[:len(signature)-len(ERC6492MagicSuffix)])
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return values[0].(common.Address), values[1].([]byte), values[2].([]byte), nil
+}
+
+// isMagicValue 返回值是左对齐的 bytes4
+func isMagicValue(output []byte) bool {
+	return len(output) >= 4 && bytes.Equal(output[:4], ERC1271MagicValue[:])
+}
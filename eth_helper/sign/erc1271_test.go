@@ -0,0 +1,195 @@
+package sign
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+)
+
+var (
+	deployedWallet       = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	counterfactualWallet = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	walletFactory        = common.HexToAddress("0x3333333333333333333333333333333333333333")
+	walletSignature      = []byte("wallet signature")
+)
+
+// newWalletServer 模拟节点：deployedWallet 已部署，counterfactualWallet 需要通过 walletFactory 部署
+// 两个钱包只认可 walletSignature
+func newWalletServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	isValid := func(data []byte) string {
+		values, err := parsedERC1271ABI.Methods["isValidSignature"].Inputs.Unpack(data[4:])
+		if err == nil && bytes.Equal(values[1].([]byte), walletSignature) {
+			return hexutil.Encode(common.RightPadBytes(ERC1271MagicValue[:], 32))
+		}
+		return hexutil.Encode(make([]byte, 32))
+	}
+	return rpctest.NewServer(t, rpctest.Handlers{
+		"eth_getCode": func(params []json.RawMessage) (interface{}, error) {
+			var address common.Address
+			_ = json.Unmarshal(params[0], &address)
+			if address == deployedWallet {
+				return "0x6080", nil
+			}
+			return "0x", nil
+		},
+		"eth_call": func(params []json.RawMessage) (interface{}, error) {
+			args := rpctest.ParseCall(params)
+			data := args.Calldata()
+			if args.To == nil {
+				// erc6492ValidatorCode：只有通过 walletFactory 部署的 counterfactualWallet 能够调用
+				data = data[len(erc6492ValidatorCode):]
+				factory, wallet := common.BytesToAddress(data[:32]), common.BytesToAddress(data[32:64])
+				if factory != walletFactory || wallet != counterfactualWallet {
+					return "0x", nil
+				}
+				data = data[128+new(big.Int).SetBytes(data[64:96]).Uint64():]
+			}
+			return isValid(data), nil
+		},
+	})
+}
+
+func wrapERC6492(t *testing.T, signature []byte) []byte {
+	t.Helper()
+	wrapped, err := erc6492Arguments.Pack(walletFactory, []byte{0xde, 0xad}, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(wrapped, ERC6492MagicSuffix...)
+}
+
+func TestVerifier_VerifyMessage(t *testing.T) {
+	eth := eth_helper.NewEthHelper(newWalletServer(t).URL)
+	defer eth.Close()
+	v := NewVerifier(eth)
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	eoa := crypto.PubkeyToAddress(key.PublicKey)
+	message := []byte("hello")
+	eoaSignature, _ := crypto.Sign(accounts.TextHash(message), key)
+
+	tests := []struct {
+		name      string
+		signer    common.Address
+		signature []byte
+		want      bool
+	}{
+		{"eoa", eoa, eoaSignature, true},
+		{"eoa with wrong signer", common.HexToAddress("0x4444444444444444444444444444444444444444"), eoaSignature, false},
+		{"deployed wallet", deployedWallet, walletSignature, true},
+		{"deployed wallet with bad signature", deployedWallet, []byte("bad"), false},
+		{"erc-6492 deployed wallet", deployedWallet, wrapERC6492(t, walletSignature), true},
+		{"erc-6492 counterfactual wallet", counterfactualWallet, wrapERC6492(t, walletSignature), true},
+		{"erc-6492 counterfactual wallet with bad signature", counterfactualWallet, wrapERC6492(t, []byte("bad")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.VerifyMessage(context.Background(), tt.signer, message, tt.signature)
+			if err != nil || got != tt.want {
+				t.Errorf("VerifyMessage() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+var (
+	// evmWalletCode 只认可以 walletSignature 开头的签名：比较 isValidSignature 中 signature 的第一个字，相等时返回魔数，否则返回 0
+	evmWalletCode = append(append([]byte{0x7f}, common.RightPadBytes(walletSignature, 32)...),
+		common.FromHex("0x60643514602d5760206000f35b631626ba7e60e01b60005260206000f3")...)
+	// evmWalletInitCode 部署 evmWalletCode 的创建代码
+	evmWalletInitCode = append([]byte{0x60, byte(len(evmWalletCode)), 0x80, 0x60, 0x0b, 0x60, 0x00, 0x39, 0x60, 0x00, 0xf3}, evmWalletCode...)
+	// evmFactoryCode 以 salt 0 CREATE2 部署 calldata 中的创建代码，部署失败时回滚
+	evmFactoryCode = common.FromHex("0x36600060003760003660006000f515601357005b600080fd")
+)
+
+// newEVMWalletServer 模拟节点：eth_call 和 eth_getCode 在进程内的 EVM 中执行，状态中只部署了 walletFactory
+// 返回通过 walletFactory 部署 evmWalletInitCode 得到的反事实钱包地址
+func newEVMWalletServer(t *testing.T) (*httptest.Server, common.Address) {
+	t.Helper()
+	base, err := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	if err != nil {
+		t.Fatal(err)
+	}
+	base.SetCode(walletFactory, evmFactoryCode)
+	server := rpctest.NewServer(t, rpctest.Handlers{
+		"eth_getCode": func(params []json.RawMessage) (interface{}, error) {
+			var address common.Address
+			_ = json.Unmarshal(params[0], &address)
+			return hexutil.Bytes(base.GetCode(address)), nil
+		},
+		"eth_call": func(params []json.RawMessage) (interface{}, error) {
+			args := rpctest.ParseCall(params)
+			// eth_call 不修改状态，每次在状态副本上执行
+			cfg := &runtime.Config{State: base.Copy()}
+			var (
+				output []byte
+				err    error
+			)
+			if args.To == nil {
+				output, _, _, err = runtime.Create(args.Calldata(), cfg)
+			} else {
+				output, _, err = runtime.Call(*args.To, args.Calldata(), cfg)
+			}
+			if errors.Is(err, vm.ErrExecutionReverted) {
+				return nil, rpctest.ErrReverted
+			}
+			if err != nil {
+				return nil, err
+			}
+			return hexutil.Bytes(output), nil
+		},
+	})
+	return server, crypto.CreateAddress2(walletFactory, [32]byte{}, crypto.Keccak256(evmWalletInitCode))
+}
+
+// TestVerifier_ERC6492InEVM 在 EVM 中执行 erc6492ValidatorCode，校验尚未部署的钱包
+func TestVerifier_ERC6492InEVM(t *testing.T) {
+	server, wallet := newEVMWalletServer(t)
+	eth := eth_helper.NewEthHelper(server.URL)
+	defer eth.Close()
+	v := NewVerifier(eth)
+	message := []byte("hello")
+	wrap := func(factoryCalldata, signature []byte) []byte {
+		wrapped, err := erc6492Arguments.Pack(walletFactory, factoryCalldata, signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(wrapped, ERC6492MagicSuffix...)
+	}
+
+	tests := []struct {
+		name      string
+		signer    common.Address
+		signature []byte
+		want      bool
+	}{
+		{"counterfactual wallet", wallet, wrap(evmWalletInitCode, walletSignature), true},
+		{"bad signature", wallet, wrap(evmWalletInitCode, []byte("bad")), false},
+		{"factory deploys another wallet", deployedWallet, wrap(evmWalletInitCode, walletSignature), false},
+		{"factory reverts", wallet, wrap([]byte{0xfe}, walletSignature), false},
+		{"not wrapped", wallet, walletSignature, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.VerifyMessage(context.Background(), tt.signer, message, tt.signature)
+			if err != nil || got != tt.want {
+				t.Errorf("VerifyMessage() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var (
+	// ErrInvalidSignatureLength 签名长度不是 65 字节，也不是 EIP-2098 的 64 字节紧凑格式
+	ErrInvalidSignatureLength = errors.New("invalid signature length")
+	// ErrInvalidRecoveryId 签名的 v 不是 0、1、27 或 28
+	ErrInvalidRecoveryId = errors.New("invalid signature v value")
+	// ErrMalleableSignature 签名的 s 大于 secp256k1n/2，属于可延展签名
+	ErrMalleableSignature = errors.New("malleable signature: s value is too high")
+	// ErrInvalidSignature r 或 s 超出取值范围
+	ErrInvalidSignature = errors.New("invalid signature r or s value")
+	// ErrInvalidHashLength 待恢复的哈希不是 32 字节
+	ErrInvalidHashLength = errors.New("invalid hash length")
+)
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// RecoverAddress 按 personal_sign（EIP-191）规则从消息签名中恢复签名地址
+func RecoverAddress(message, signature []byte) (common.Address, error) {
+	return RecoverHash(accounts.TextHash(message), signature)
+}
+
+// RecoverHash 从 32 字节哈希的签名中恢复签名地址，对应 eth_sign 直接签名哈希的场景
+func RecoverHash(hash, signature []byte) (common.Address, error) {
+	return recoverHash(hash, signature, false)
+}
+
+// recoverHash 从哈希的签名中恢复签名地址，allowHighS 为 true 时接受可延展签名
+func recoverHash(hash, signature []byte, allowHighS bool) (common.Address, error) {
+	if len(hash) != common.HashLength {
+		return common.Address{}, fmt.Errorf("%w: %d", ErrInvalidHashLength, len(hash))
+	}
+	sig, err := normalizeSignature(signature, allowHighS)
+	if err != nil {
+		return common.Address{}, err
+	}
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+// RecoverTypedData 从 EIP-712 签名中恢复签名地址
+func RecoverTypedData(typedData apitypes.TypedData, signature []byte) (common.Address, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return RecoverHash(hash.Bytes(), signature)
+}
+
+// NormalizeSignature 校验签名并转换为 65 字节 [R || S || V] 格式，V 为 0 或 1，不修改传入的 signature
+// 支持 65 字节签名和 EIP-2098 的 64 字节紧凑签名，拒绝 s 大于 secp256k1n/2 的可延展签名
+func NormalizeSignature(signature []byte) ([]byte, error) {
+	return normalizeSignature(signature, false)
+}
+
+func normalizeSignature(signature []byte, allowHighS bool) ([]byte, error) {
+	sig := make([]byte, 65)
+	switch len(signature) {
+	case 65:
+		copy(sig, signature)
+		switch v := sig[64]; v {
+		case 0, 1:
+		case 27, 28:
+			sig[64] = v - 27
+		default:
+			return nil, fmt.Errorf("%w: %d", ErrInvalidRecoveryId, v)
+		}
+	case 64:
+		// EIP-2098：yParityAndS 的最高位是 yParity，其余位是 s
+		copy(sig, signature[:64])
+		sig[64] = sig[32] >> 7
+		sig[32] &= 0x7f
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrInvalidSignatureLength, len(signature))
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(secp256k1N) >= 0 {
+		return nil, ErrInvalidSignature
+	}
+	if s.Cmp(secp256k1N) >= 0 {
+		return nil, ErrInvalidSignature
+	}
+	if !allowHighS && s.Cmp(secp256k1HalfN) > 0 {
+		return nil, ErrMalleableSignature
+	}
+	return sig, nil
+}
@@ -0,0 +1,88 @@
+package sign
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestRecoverAddress(t *testing.T) {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	want := crypto.PubkeyToAddress(key.PublicKey)
+	message := []byte("hello")
+	raw, _ := crypto.Sign(accounts.TextHash(message), key)
+
+	withV := func(v byte) []byte {
+		sig := append([]byte{}, raw...)
+		sig[64] = v
+		return sig
+	}
+	compact := append([]byte{}, raw[:64]...)
+	compact[32] |= raw[64] << 7
+	// 可延展签名：s' = n - s，同时翻转 v
+	highS := append([]byte{}, raw...)
+	s := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(raw[32:64]))
+	copy(highS[32:64], common.LeftPadBytes(s.Bytes(), 32))
+	highS[64] ^= 1
+
+	tests := []struct {
+		name      string
+		signature []byte
+		wantErr   error
+	}{
+		{"v 0/1", raw, nil},
+		{"v 27/28", withV(raw[64] + 27), nil},
+		{"eip-2098 compact", compact, nil},
+		{"too short", raw[:10], ErrInvalidSignatureLength},
+		{"empty", nil, ErrInvalidSignatureLength},
+		{"bad v", withV(29), ErrInvalidRecoveryId},
+		{"high s", highS, ErrMalleableSignature},
+		{"zero r", append(make([]byte, 32), raw[32:]...), ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := append([]byte{}, tt.signature...)
+			got, err := RecoverAddress(message, tt.signature)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RecoverAddress() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != want {
+				t.Errorf("RecoverAddress() = %s, want %s", got, want)
+			}
+			if !bytes.Equal(before, tt.signature) {
+				t.Errorf("RecoverAddress() modified the signature")
+			}
+			// VerifySignature 仍然接受可延展签名
+			wantOK := tt.wantErr == nil || tt.wantErr == ErrMalleableSignature
+			if ok := VerifySignature(string(message), hexutil.Encode(tt.signature), want); ok != wantOK {
+				t.Errorf("VerifySignature() = %v, want %v", ok, wantOK)
+			}
+		})
+	}
+}
+
+func TestRecoverHash(t *testing.T) {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	hash := crypto.Keccak256([]byte("hello"))
+	sig, _ := crypto.Sign(hash, key)
+	if got, err := RecoverHash(hash, sig); err != nil || got != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("RecoverHash() = %s, %v", got, err)
+	}
+	if _, err := RecoverHash(hash[:31], sig); !errors.Is(err, ErrInvalidHashLength) {
+		t.Errorf("RecoverHash() error = %v, want ErrInvalidHashLength", err)
+	}
+}
+
+func TestRecoverTypedData(t *testing.T) {
+	want := crypto.PubkeyToAddress(crypto.ToECDSAUnsafe(crypto.Keccak256([]byte("cow"))).PublicKey)
+	got, err := RecoverTypedData(mailTypedData(t), common.FromHex(mailSignature))
+	if err != nil || got != want {
+		t.Errorf("RecoverTypedData() = %s, %v, want %s", got, err, want)
+	}
+}
@@ -1,34 +1,24 @@
 package sign
 
 import (
+	"context"
 	"crypto/ecdsa"
-	"fmt"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
 )
 
+// VerifySignature 校验 personal_sign 签名是否由 signerAddress 签出，签名格式错误时返回 false
+// 为了兼容已有调用方，仍然接受 s 大于 secp256k1n/2 的可延展签名，需要拒绝时使用 RecoverAddress
 func VerifySignature(message, signatureHex string, signerAddress common.Address) bool {
-	signature := common.FromHex(signatureHex)
-
-	message = fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
-	// 消息的哈希值
-	hashedMessage := crypto.Keccak256Hash([]byte(message))
-
-	if signature[64] >= 27 {
-		signature[64] -= 27
-	}
-	// 从签名中提取公钥
-	pubKey, err := crypto.SigToPub(hashedMessage.Bytes(), signature)
+	recoveredAddress, err := recoverHash(accounts.TextHash([]byte(message)), common.FromHex(signatureHex), true)
 	if err != nil {
 		return false
 	}
-	// 从公钥中计算地址
-	recoveredAddress := crypto.PubkeyToAddress(*pubKey)
-	// 比较恢复的地址和给定的签名者地址
-	res := signerAddress.Cmp(recoveredAddress)
-	return res == 0
+	return recoveredAddress == signerAddress
 }
 
 func PrivateKeyStr2EcdsaPrivateKey(privateKeyStr string) *ecdsa.PrivateKey {
@@ -36,12 +26,12 @@ func PrivateKeyStr2EcdsaPrivateKey(privateKeyStr string) *ecdsa.PrivateKey {
 	return privateKey
 }
 
-func Sign(signMessage []byte, privateKey *ecdsa.PrivateKey) (string, error) {
-	signatureByte, err := crypto.Sign(signMessage[:], privateKey)
-	if signatureByte[64] == 0 {
-		signatureByte[64] = 27
-	} else {
-		signatureByte[64] = 28
+// Sign 使用签名器签名 32 字节哈希，返回 V 为 27 或 28 的十六进制签名
+func Sign(ctx context.Context, hash []byte, signer eth_interface.HashSigner) (string, error) {
+	signatureByte, err := signer.SignHash(ctx, hash)
+	if err != nil {
+		return "", err
 	}
-	return hexutil.Encode(signatureByte), err
+	signatureByte[64] += 27
+	return hexutil.Encode(signatureByte), nil
 }
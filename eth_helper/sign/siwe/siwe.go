@@ -0,0 +1,311 @@
+// Package siwe 实现 Sign-In with Ethereum（EIP-4361）消息的构建、解析、校验和签名验证
+package siwe
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/web3coderecho/web3_helper/eth_helper/sign"
+)
+
+var (
+	// ErrInvalidMessage 消息不符合 EIP-4361 格式
+	ErrInvalidMessage = errors.New("siwe: invalid message")
+	// ErrDomainMismatch 消息中的 domain 与期望不一致
+	ErrDomainMismatch = errors.New("siwe: domain mismatch")
+	// ErrNonceMismatch 消息中的 nonce 与期望不一致
+	ErrNonceMismatch = errors.New("siwe: nonce mismatch")
+	// ErrChainIdMismatch 消息中的 chain ID 与期望不一致
+	ErrChainIdMismatch = errors.New("siwe: chain ID mismatch")
+	// ErrExpired 消息已经超过 Expiration Time
+	ErrExpired = errors.New("siwe: message expired")
+	// ErrNotYetValid 消息还没有到 Not Before
+	ErrNotYetValid = errors.New("siwe: message not yet valid")
+	// ErrInvalidSignature 签名不是由消息中的地址签出
+	ErrInvalidSignature = errors.New("siwe: invalid signature")
+)
+
+const (
+	headerSuffix = " wants you to sign in with your Ethereum account:"
+	nonceChars   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// Message EIP-4361 消息，时间字段为零值表示未设置
+type Message struct {
+	Scheme         string // 可选，如 https
+	Domain         string
+	Address        common.Address
+	Statement      string // 可选，不能包含换行
+	URI            string
+	Version        string // 目前只有 "1"
+	ChainID        uint64
+	Nonce          string // 至少 8 位字母或数字
+	IssuedAt       time.Time
+	ExpirationTime time.Time
+	NotBefore      time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// ValidateOptions 校验消息时的期望值，字段为零值时不校验对应内容
+type ValidateOptions struct {
+	Domain  string
+	Nonce   string
+	ChainID uint64
+	Time    time.Time // 判断有效期使用的时间，默认当前时间
+}
+
+// NewMessage 创建 Version 为 1、IssuedAt 为当前时间并带有随机 nonce 的消息
+func NewMessage(domain string, address common.Address, uri string, chainID uint64) (*Message, error) {
+	nonce, err := GenerateNonce()
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		Domain:   domain,
+		Address:  address,
+		URI:      uri,
+		Version:  "1",
+		ChainID:  chainID,
+		Nonce:    nonce,
+		IssuedAt: time.Now().UTC(),
+	}, nil
+}
+
+// GenerateNonce 生成 17 位随机字母数字 nonce
+func GenerateNonce() (string, error) {
+	max := big.NewInt(int64(len(nonceChars)))
+	nonce := make([]byte, 17)
+	for i := range nonce {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate nonce: %v", err)
+		}
+		nonce[i] = nonceChars[n.Int64()]
+	}
+	return string(nonce), nil
+}
+
+// String 按 EIP-4361 格式输出消息，即需要签名的文本
+func (m *Message) String() string {
+	var b strings.Builder
+	if m.Scheme != "" {
+		b.WriteString(m.Scheme + "://")
+	}
+	b.WriteString(m.Domain + headerSuffix + "\n")
+	b.WriteString(m.Address.Hex() + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString("URI: " + m.URI + "\n")
+	b.WriteString("Version: " + m.Version + "\n")
+	b.WriteString("Chain ID: " + strconv.FormatUint(m.ChainID, 10) + "\n")
+	b.WriteString("Nonce: " + m.Nonce + "\n")
+	b.WriteString("Issued At: " + formatTime(m.IssuedAt))
+	if !m.ExpirationTime.IsZero() {
+		b.WriteString("\nExpiration Time: " + formatTime(m.ExpirationTime))
+	}
+	if !m.NotBefore.IsZero() {
+		b.WriteString("\nNot Before: " + formatTime(m.NotBefore))
+	}
+	if m.RequestID != "" {
+		b.WriteString("\nRequest ID: " + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			b.WriteString("\n- " + resource)
+		}
+	}
+	return b.String()
+}
+
+// ParseMessage 解析 EIP-4361 消息
+func ParseMessage(message string) (*Message, error) {
+	lines := strings.Split(message, "\n")
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidMessage, fmt.Sprintf(format, args...))
+	}
+	if len(lines) < 9 {
+		return nil, invalid("too few lines")
+	}
+	m := &Message{}
+	header, ok := strings.CutSuffix(lines[0], headerSuffix)
+	if !ok {
+		return nil, invalid("missing header")
+	}
+	if scheme, domain, found := strings.Cut(header, "://"); found {
+		m.Scheme, m.Domain = scheme, domain
+	} else {
+		m.Domain = header
+	}
+	if m.Domain == "" {
+		return nil, invalid("empty domain")
+	}
+	if !common.IsHexAddress(lines[1]) || common.HexToAddress(lines[1]).Hex() != lines[1] {
+		return nil, invalid("address %q is not EIP-55 checksummed", lines[1])
+	}
+	m.Address = common.HexToAddress(lines[1])
+	if lines[2] != "" {
+		return nil, invalid("missing empty line after address")
+	}
+	idx := 4
+	if lines[3] != "" {
+		m.Statement = lines[3]
+		if lines[4] != "" {
+			return nil, invalid("missing empty line after statement")
+		}
+		idx = 5
+	}
+	// field 读取下一行的必填或可选字段
+	field := func(name string, required bool) (string, error) {
+		if idx < len(lines) {
+			if value, found := strings.CutPrefix(lines[idx], name+": "); found {
+				idx++
+				return value, nil
+			}
+		}
+		if required {
+			return "", invalid("missing %s", name)
+		}
+		return "", nil
+	}
+	var err error
+	if m.URI, err = field("URI", true); err != nil {
+		return nil, err
+	}
+	if m.Version, err = field("Version", true); err != nil {
+		return nil, err
+	}
+	if m.Version != "1" {
+		return nil, invalid("unsupported version %q", m.Version)
+	}
+	chainID, err := field("Chain ID", true)
+	if err != nil {
+		return nil, err
+	}
+	if m.ChainID, err = strconv.ParseUint(chainID, 10, 64); err != nil {
+		return nil, invalid("invalid chain ID %q", chainID)
+	}
+	if m.Nonce, err = field("Nonce", true); err != nil {
+		return nil, err
+	}
+	if !validNonce(m.Nonce) {
+		return nil, invalid("nonce must be at least 8 alphanumeric characters")
+	}
+	for _, item := range []struct {
+		name     string
+		required bool
+		target   *time.Time
+	}{
+		{"Issued At", true, &m.IssuedAt},
+		{"Expiration Time", false, &m.ExpirationTime},
+		{"Not Before", false, &m.NotBefore},
+	} {
+		value, err := field(item.name, item.required)
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			continue
+		}
+		if *item.target, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			return nil, invalid("invalid %s %q", item.name, value)
+		}
+	}
+	if m.RequestID, err = field("Request ID", false); err != nil {
+		return nil, err
+	}
+	if idx < len(lines) && lines[idx] == "Resources:" {
+		for idx++; idx < len(lines); idx++ {
+			resource, found := strings.CutPrefix(lines[idx], "- ")
+			if !found {
+				return nil, invalid("invalid resource %q", lines[idx])
+			}
+			m.Resources = append(m.Resources, resource)
+		}
+	}
+	if idx != len(lines) {
+		return nil, invalid("unexpected line %q", lines[idx])
+	}
+	return m, nil
+}
+
+// Validate 校验消息的 domain、nonce、chain ID 和有效期
+func (m *Message) Validate(opts ValidateOptions) error {
+	if opts.Domain != "" && m.Domain != opts.Domain {
+		return fmt.Errorf("%w: got %s, want %s", ErrDomainMismatch, m.Domain, opts.Domain)
+	}
+	if opts.Nonce != "" && m.Nonce != opts.Nonce {
+		return ErrNonceMismatch
+	}
+	if opts.ChainID != 0 && m.ChainID != opts.ChainID {
+		return fmt.Errorf("%w: got %d, want %d", ErrChainIdMismatch, m.ChainID, opts.ChainID)
+	}
+	now := opts.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if !m.ExpirationTime.IsZero() && !now.Before(m.ExpirationTime) {
+		return ErrExpired
+	}
+	if !m.NotBefore.IsZero() && now.Before(m.NotBefore) {
+		return ErrNotYetValid
+	}
+	return nil
+}
+
+// Verify 解析并校验消息，然后验证签名，成功时返回解析后的消息
+// verifier 为 nil 时只支持 EOA 签名，否则同时支持 EIP-1271 合约钱包和 ERC-6492 签名
+// 签名按原始消息文本校验，不会重新格式化消息
+func Verify(ctx context.Context, verifier *sign.Verifier, message string, signature []byte, opts ValidateOptions) (*Message, error) {
+	m, err := ParseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if err = m.Validate(opts); err != nil {
+		return nil, err
+	}
+	if verifier == nil {
+		recovered, err := sign.RecoverAddress([]byte(message), signature)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+		if recovered != m.Address {
+			return nil, ErrInvalidSignature
+		}
+		return m, nil
+	}
+	ok, err := verifier.VerifyMessage(ctx, m.Address, []byte(message), signature)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+	return m, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func validNonce(nonce string) bool {
+	if len(nonce) < 8 {
+		return false
+	}
+	for _, c := range nonce {
+		if !strings.ContainsRune(nonceChars, c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package siwe
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// exampleMessage EIP-4361 规范中的示例
+const exampleMessage = `service.org wants you to sign in with your Ethereum account:
+0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2
+
+I accept the ServiceOrg Terms of Service: https://service.org/tos
+
+URI: https://service.org/login
+Version: 1
+Chain ID: 1
+Nonce: 32891756
+Issued At: 2021-09-30T16:25:24Z
+Resources:
+- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/
+- https://example.com/my-web2-claim.json`
+
+func TestParseMessage(t *testing.T) {
+	m, err := ParseMessage(exampleMessage)
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	want := &Message{
+		Domain:    "service.org",
+		Address:   common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"),
+		Statement: "I accept the ServiceOrg Terms of Service: https://service.org/tos",
+		URI:       "https://service.org/login",
+		Version:   "1",
+		ChainID:   1,
+		Nonce:     "32891756",
+		IssuedAt:  time.Date(2021, 9, 30, 16, 25, 24, 0, time.UTC),
+		Resources: []string{
+			"ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/",
+			"https://example.com/my-web2-claim.json",
+		},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("ParseMessage() = %+v, want %+v", m, want)
+	}
+	if got := m.String(); got != exampleMessage {
+		t.Errorf("String() = %q, want %q", got, exampleMessage)
+	}
+}
+
+func TestParseMessage_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{"empty", ""},
+		{"lowercase address", "service.org wants you to sign in with your Ethereum account:\n0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2\n\n\nURI: https://service.org\nVersion: 1\nChain ID: 1\nNonce: 32891756\nIssued At: 2021-09-30T16:25:24Z"},
+		{"short nonce", "service.org wants you to sign in with your Ethereum account:\n0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2\n\n\nURI: https://service.org\nVersion: 1\nChain ID: 1\nNonce: 123\nIssued At: 2021-09-30T16:25:24Z"},
+		{"missing issued at", "service.org wants you to sign in with your Ethereum account:\n0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2\n\n\nURI: https://service.org\nVersion: 1\nChain ID: 1\nNonce: 32891756\n"},
+		{"trailing garbage", exampleMessage + "\nfoo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseMessage(tt.message); !errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("ParseMessage() error = %v, want ErrInvalidMessage", err)
+			}
+		})
+	}
+}
+
+func TestMessage_Validate(t *testing.T) {
+	issuedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := &Message{
+		Domain:         "example.com",
+		ChainID:        1,
+		Nonce:          "abcdefgh1",
+		IssuedAt:       issuedAt,
+		NotBefore:      issuedAt.Add(time.Minute),
+		ExpirationTime: issuedAt.Add(time.Hour),
+	}
+	valid := issuedAt.Add(30 * time.Minute)
+	tests := []struct {
+		name    string
+		opts    ValidateOptions
+		wantErr error
+	}{
+		{"valid", ValidateOptions{Domain: "example.com", Nonce: "abcdefgh1", ChainID: 1, Time: valid}, nil},
+		{"domain", ValidateOptions{Domain: "evil.com", Time: valid}, ErrDomainMismatch},
+		{"nonce", ValidateOptions{Nonce: "other", Time: valid}, ErrNonceMismatch},
+		{"chain id", ValidateOptions{ChainID: 56, Time: valid}, ErrChainIdMismatch},
+		{"expired", ValidateOptions{Time: issuedAt.Add(time.Hour)}, ErrExpired},
+		{"not before", ValidateOptions{Time: issuedAt}, ErrNotYetValid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := m.Validate(tt.opts); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	m, err := NewMessage("example.com", crypto.PubkeyToAddress(key.PublicKey), "https://example.com/login", 1)
+	if err != nil {
+		t.Fatalf("NewMessage() error = %v", err)
+	}
+	m.Statement = "Sign in to Example"
+	message := m.String()
+	signature, _ := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	signature[64] += 27
+
+	got, err := Verify(context.Background(), nil, message, signature, ValidateOptions{Domain: "example.com", Nonce: m.Nonce})
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got.Address != m.Address || got.Nonce != m.Nonce {
+		t.Errorf("Verify() = %+v, want %+v", got, m)
+	}
+	m.Address = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	if _, err = Verify(context.Background(), nil, m.String(), signature, ValidateOptions{}); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() with another address error = %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,225 @@
+package sign
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+)
+
+// ErrChainIdMismatch EIP-712 domain 中的 chainId 与当前链不一致
+var ErrChainIdMismatch = errors.New("typed data chainId does not match the connected chain")
+
+// ParseTypedData 解析 eth_signTypedData_v4 格式的 JSON
+func ParseTypedData(data []byte) (apitypes.TypedData, error) {
+	var typedData apitypes.TypedData
+	if err := json.Unmarshal(data, &typedData); err != nil {
+		return apitypes.TypedData{}, fmt.Errorf("failed to parse typed data: %v", err)
+	}
+	return typedData, nil
+}
+
+// NewTypedData 使用 Go 结构体构建 TypedData
+// message 可以是 map[string]interface{} 或结构体，结构体字段名取 eip712 或 json 标签，没有标签时使用首字母小写的字段名
+// types 中没有 EIP712Domain 时按 domain 中已设置的字段自动补充
+func NewTypedData(domain apitypes.TypedDataDomain, types apitypes.Types, primaryType string, message interface{}) (apitypes.TypedData, error) {
+	msg, err := toMessage(message)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+	allTypes := make(apitypes.Types, len(types)+1)
+	for name, fields := range types {
+		allTypes[name] = fields
+	}
+	if _, ok := allTypes["EIP712Domain"]; !ok {
+		allTypes["EIP712Domain"] = domainType(domain)
+	}
+	return apitypes.TypedData{Types: allTypes, PrimaryType: primaryType, Domain: domain, Message: msg}, nil
+}
+
+// TypedDataHash 计算 EIP-712 摘要 keccak256("\x19\x01" || domainSeparator || hashStruct(message))
+func TypedDataHash(typedData apitypes.TypedData) (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash typed data: %v", err)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// ValidateChainId 校验 domain 中的 chainId 与 eth 连接的链一致，domain 没有 chainId 时不校验
+func ValidateChainId(ctx context.Context, eth *eth_helper.EthHelper, typedData apitypes.TypedData) error {
+	if typedData.Domain.ChainId == nil {
+		return nil
+	}
+	chainId, err := eth.GetChainId(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %v", err)
+	}
+	if (*big.Int)(typedData.Domain.ChainId).Cmp(chainId) != 0 {
+		return fmt.Errorf("%w: domain %s, chain %s", ErrChainIdMismatch, (*big.Int)(typedData.Domain.ChainId), chainId)
+	}
+	return nil
+}
+
+// SignTypedData 按 EIP-712 签名结构化数据，返回 V 为 27 或 28 的十六进制签名
+// eth 不为 nil 时先校验 domain 中的 chainId
+func SignTypedData(ctx context.Context, eth *eth_helper.EthHelper, signer eth_interface.SignerInterface, typedData apitypes.TypedData) (string, error) {
+	if eth != nil {
+		if err := ValidateChainId(ctx, eth, typedData); err != nil {
+			return "", err
+		}
+	}
+	signature, err := signer.SignTypedData(ctx, typedData)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(signature), nil
+}
+
+// VerifyTypedData 校验 EIP-712 签名是否由 signerAddress 签出
+// eth 不为 nil 时先校验 domain 中的 chainId
+func VerifyTypedData(ctx context.Context, eth *eth_helper.EthHelper, typedData apitypes.TypedData, signatureHex string, signerAddress common.Address) (bool, error) {
+	if eth != nil {
+		if err := ValidateChainId(ctx, eth, typedData); err != nil {
+			return false, err
+		}
+	}
+	recoveredAddress, err := RecoverTypedData(typedData, common.FromHex(signatureHex))
+	if err != nil {
+		return false, err
+	}
+	return recoveredAddress == signerAddress, nil
+}
+
+// domainType 按 domain 中已设置的字段生成 EIP712Domain 类型
+func domainType(domain apitypes.TypedDataDomain) []apitypes.Type {
+	fields := make([]apitypes.Type, 0, 5)
+	if domain.Name != "" {
+		fields = append(fields, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if domain.Version != "" {
+		fields = append(fields, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if domain.ChainId != nil {
+		fields = append(fields, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if domain.VerifyingContract != "" {
+		fields = append(fields, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	if domain.Salt != "" {
+		fields = append(fields, apitypes.Type{Name: "salt", Type: "bytes32"})
+	}
+	return fields
+}
+
+var (
+	bigIntType  = reflect.TypeOf((*big.Int)(nil))
+	addressType = reflect.TypeOf(common.Address{})
+)
+
+// toMessage 把结构体转换为 TypedData 使用的 map
+func toMessage(message interface{}) (apitypes.TypedDataMessage, error) {
+	if msg, ok := message.(map[string]interface{}); ok {
+		return msg, nil
+	}
+	value, err := toValue(reflect.ValueOf(message))
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("message must be a struct or map, got %T", message)
+	}
+	return msg, nil
+}
+
+// toValue 把 Go 值转换为 apitypes 能编码的值
+func toValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, errors.New("nil value in typed data message")
+	}
+	switch {
+	case v.Type() == bigIntType:
+		if v.IsNil() {
+			return nil, errors.New("nil *big.Int in typed data message")
+		}
+		return v.Interface(), nil
+	case v.Type() == addressType:
+		return v.Interface().(common.Address).Hex(), nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, errors.New("nil value in typed data message")
+		}
+		return toValue(v.Elem())
+	case reflect.Bool, reflect.String:
+		return v.Interface(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(v.Uint()), nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return b, nil
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			item, err := toValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	case reflect.Map:
+		if msg, ok := v.Interface().(map[string]interface{}); ok {
+			return msg, nil
+		}
+	case reflect.Struct:
+		msg := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := fieldName(field)
+			if name == "-" {
+				continue
+			}
+			value, err := toValue(v.Field(i))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", name, err)
+			}
+			msg[name] = value
+		}
+		return msg, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s in typed data message", v.Type())
+}
+
+// fieldName 依次使用 eip712 标签、json 标签和首字母小写的字段名
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"eip712", "json"} {
+		if tag, ok := field.Tag.Lookup(key); ok {
+			if name, _, _ := strings.Cut(tag, ","); name != "" {
+				return name
+			}
+		}
+	}
+	name := []rune(field.Name)
+	name[0] = unicode.ToLower(name[0])
+	return string(name)
+}
@@ -0,0 +1,131 @@
+package sign
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+	"github.com/web3coderecho/web3_helper/eth_helper/signer"
+)
+
+// mailJSON EIP-712 规范中的示例
+const mailJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [{"name": "name", "type": "string"}, {"name": "wallet", "type": "address"}],
+		"Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "string"}]
+	},
+	"primaryType": "Mail",
+	"domain": {"name": "Ether Mail", "version": "1", "chainId": 1, "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+const (
+	mailDigest    = "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"
+	mailSignature = "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+)
+
+type person struct {
+	Name   string
+	Wallet common.Address
+}
+
+type mail struct {
+	From     person
+	To       person
+	Contents string
+}
+
+func mailTypedData(t *testing.T) apitypes.TypedData {
+	t.Helper()
+	typedData, err := NewTypedData(
+		apitypes.TypedDataDomain{Name: "Ether Mail", Version: "1", ChainId: math.NewHexOrDecimal256(1), VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+		apitypes.Types{
+			"Person": {{Name: "name", Type: "string"}, {Name: "wallet", Type: "address"}},
+			"Mail":   {{Name: "from", Type: "Person"}, {Name: "to", Type: "Person"}, {Name: "contents", Type: "string"}},
+		},
+		"Mail",
+		mail{
+			From:     person{Name: "Cow", Wallet: common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")},
+			To:       person{Name: "Bob", Wallet: common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB")},
+			Contents: "Hello, Bob!",
+		},
+	)
+	if err != nil {
+		t.Fatalf("NewTypedData() error = %v", err)
+	}
+	return typedData
+}
+
+func TestTypedDataHash(t *testing.T) {
+	fromJSON, err := ParseTypedData([]byte(mailJSON))
+	if err != nil {
+		t.Fatalf("ParseTypedData() error = %v", err)
+	}
+	tests := []struct {
+		name      string
+		typedData apitypes.TypedData
+	}{
+		{"json", fromJSON},
+		{"struct", mailTypedData(t)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TypedDataHash(tt.typedData)
+			if err != nil || got.Hex() != mailDigest {
+				t.Errorf("TypedDataHash() = %s, %v, want %s", got.Hex(), err, mailDigest)
+			}
+		})
+	}
+}
+
+func TestSignTypedData(t *testing.T) {
+	key := crypto.Keccak256([]byte("cow"))
+	s, _ := signer.NewPrivateKeySignerFromHex(common.Bytes2Hex(key))
+	typedData := mailTypedData(t)
+
+	signature, err := SignTypedData(context.Background(), nil, s, typedData)
+	if err != nil || signature != mailSignature {
+		t.Fatalf("SignTypedData() = %s, %v, want %s", signature, err, mailSignature)
+	}
+	ok, err := VerifyTypedData(context.Background(), nil, typedData, signature, s.Address())
+	if err != nil || !ok {
+		t.Errorf("VerifyTypedData() = %v, %v, want true", ok, err)
+	}
+	ok, _ = VerifyTypedData(context.Background(), nil, typedData, signature, common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"))
+	if ok {
+		t.Errorf("VerifyTypedData() with another address = true, want false")
+	}
+}
+
+func TestValidateChainId(t *testing.T) {
+	server := rpctest.NewServer(t, rpctest.Handlers{"eth_chainId": rpctest.Result("0x38")})
+	eth := eth_helper.NewEthHelper(server.URL)
+	defer eth.Close()
+	key := crypto.Keccak256([]byte("cow"))
+	s, _ := signer.NewPrivateKeySignerFromHex(common.Bytes2Hex(key))
+
+	_, err := SignTypedData(context.Background(), eth, s, mailTypedData(t))
+	if !errors.Is(err, ErrChainIdMismatch) {
+		t.Errorf("SignTypedData() error = %v, want ErrChainIdMismatch", err)
+	}
+	_, err = VerifyTypedData(context.Background(), eth, mailTypedData(t), mailSignature, s.Address())
+	if !errors.Is(err, ErrChainIdMismatch) {
+		t.Errorf("VerifyTypedData() error = %v, want ErrChainIdMismatch", err)
+	}
+}
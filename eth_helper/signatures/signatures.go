@@ -0,0 +1,282 @@
+package signatures
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc1155"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc721"
+)
+
+// abis 内置的常用 DeFi 合约 ABI：WETH、Uniswap V2/V3、ERC4626、Multicall3、Permit、Ownable
+//
+//go:embed abis/*.json
+var abis embed.FS
+
+// ErrUnknownEvent 注册表中没有与日志匹配的事件
+var ErrUnknownEvent = errors.New("unknown event topic")
+
+var (
+	defaultOnce     sync.Once
+	defaultRegistry *Registry
+)
+
+// Registry 函数选择器和事件 topic0 到 ABI 定义的映射，可以并发使用
+// 同一个选择器或 topic0 可能对应多个定义，后注册的排在前面
+type Registry struct {
+	mu       sync.RWMutex
+	methods  map[[4]byte][]abi.Method
+	events   map[common.Hash][]abi.Event
+	fallback *Registry // 本注册表之后继续查找的注册表，可以为 nil
+}
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		methods: make(map[[4]byte][]abi.Method),
+		events:  make(map[common.Hash][]abi.Event),
+	}
+}
+
+// NewRegistryWithFallback 创建空的注册表，查找时先查本注册表再查 fallback
+// 注册只写入本注册表，不影响 fallback
+func NewRegistryWithFallback(fallback *Registry) *Registry {
+	r := NewRegistry()
+	r.fallback = fallback
+	return r
+}
+
+// NewDefaultRegistry 创建包含内置 ERC20/ERC721/ERC1155 和常用 DeFi ABI 的注册表
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, metaData := range []*bind.MetaData{erc20.Erc20MetaData, erc1155.Erc1155MetaData, erc721.Erc721MetaData} {
+		if parsed, err := metaData.GetAbi(); err == nil {
+			r.Register(parsed)
+		}
+	}
+	entries, _ := abis.ReadDir("abis")
+	for _, entry := range entries {
+		data, err := abis.ReadFile("abis/" + entry.Name())
+		if err != nil {
+			continue
+		}
+		_ = r.RegisterJSON(string(data))
+	}
+	return r
+}
+
+// Default 返回进程内共享的默认注册表，tx 解码器和 Scan 默认使用它
+// 在 Default 中注册的 ABI 对所有使用默认注册表的地方生效
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		defaultRegistry = NewDefaultRegistry()
+	})
+	return defaultRegistry
+}
+
+// Register 注册 ABI 中的方法和事件，完全相同的定义只保留一份
+func (r *Registry) Register(contractABI *abi.ABI) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, method := range contractABI.Methods {
+		var selector [4]byte
+		copy(selector[:], method.ID)
+		r.methods[selector] = prependMethod(r.methods[selector], method)
+	}
+	for _, event := range contractABI.Events {
+		if event.Anonymous {
+			continue
+		}
+		r.events[event.ID] = prependEvent(r.events[event.ID], event)
+	}
+}
+
+// RegisterJSON 解析 JSON ABI 并注册
+func (r *Registry) RegisterJSON(abiJSON string) error {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return fmt.Errorf("failed to parse ABI: %v", err)
+	}
+	r.Register(&parsed)
+	return nil
+}
+
+// LoadFile 从文件加载 ABI，支持纯 ABI 数组和 Hardhat/Truffle/Foundry 编译产物（读取其中的 abi 字段）
+func (r *Registry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal(data, &artifact); err != nil || len(artifact.ABI) == 0 {
+			return fmt.Errorf("%s: no abi field in artifact", path)
+		}
+		data = artifact.ABI
+	}
+	if err := r.RegisterJSON(string(data)); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// LoadDir 加载目录（包含子目录）下所有 .json 文件中的 ABI
+func (r *Registry) LoadDir(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+		return r.LoadFile(path)
+	})
+}
+
+// Methods 返回选择器对应的全部方法，后注册的在前，fallback 中的方法排在最后
+func (r *Registry) Methods(selector [4]byte) []abi.Method {
+	r.mu.RLock()
+	own := append([]abi.Method(nil), r.methods[selector]...)
+	r.mu.RUnlock()
+	if r.fallback == nil {
+		return own
+	}
+	methods := r.fallback.Methods(selector)
+	for i := len(own) - 1; i >= 0; i-- {
+		methods = prependMethod(methods, own[i])
+	}
+	return methods
+}
+
+// Method 返回选择器对应的方法，有多个时返回最后注册的
+func (r *Registry) Method(selector [4]byte) (abi.Method, bool) {
+	methods := r.Methods(selector)
+	if len(methods) == 0 {
+		return abi.Method{}, false
+	}
+	return methods[0], true
+}
+
+// MethodSignature 返回调用数据前 4 字节对应的方法签名，如 transfer(address,uint256)
+func (r *Registry) MethodSignature(data []byte) (string, bool) {
+	if len(data) < 4 {
+		return "", false
+	}
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	method, ok := r.Method(selector)
+	return method.Sig, ok
+}
+
+// Events 返回 topic0 对应的全部事件，后注册的在前，fallback 中的事件排在最后
+func (r *Registry) Events(topic common.Hash) []abi.Event {
+	r.mu.RLock()
+	own := append([]abi.Event(nil), r.events[topic]...)
+	r.mu.RUnlock()
+	if r.fallback == nil {
+		return own
+	}
+	events := r.fallback.Events(topic)
+	for i := len(own) - 1; i >= 0; i-- {
+		events = prependEvent(events, own[i])
+	}
+	return events
+}
+
+// Event 返回 topic0 对应的事件，有多个时返回最后注册的
+func (r *Registry) Event(topic common.Hash) (abi.Event, bool) {
+	events := r.Events(topic)
+	if len(events) == 0 {
+		return abi.Event{}, false
+	}
+	return events[0], true
+}
+
+// DecodeLog 根据 topic0 识别事件并解析参数
+// ERC20 和 ERC721 的 Transfer 等事件 topic0 相同，按 indexed 参数个数选择匹配的定义
+func (r *Registry) DecodeLog(log types.Log) (abi.Event, map[string]interface{}, error) {
+	if len(log.Topics) == 0 {
+		return abi.Event{}, nil, fmt.Errorf("%w: log has no topics", ErrUnknownEvent)
+	}
+	var lastErr error
+	for _, event := range r.Events(log.Topics[0]) {
+		values, err := unpackLog(event, log)
+		if err == nil {
+			return event, values, nil
+		}
+		lastErr = err
+	}
+	if lastErr != nil {
+		return abi.Event{}, nil, lastErr
+	}
+	return abi.Event{}, nil, fmt.Errorf("%w %s", ErrUnknownEvent, log.Topics[0].Hex())
+}
+
+// unpackLog 解析日志的 indexed 参数和 data
+func unpackLog(event abi.Event, log types.Log) (map[string]interface{}, error) {
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if len(indexed) != len(log.Topics)-1 {
+		return nil, fmt.Errorf("%s: expected %d indexed arguments, got %d", event.Sig, len(indexed), len(log.Topics)-1)
+	}
+	values := make(map[string]interface{})
+	if err := event.Inputs.NonIndexed().UnpackIntoMap(values, log.Data); err != nil {
+		return nil, fmt.Errorf("failed to unpack %s data: %v", event.Sig, err)
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("failed to parse %s topics: %v", event.Sig, err)
+	}
+	return values, nil
+}
+
+// prependMethod 把 method 放到最前面，已有完全相同的定义时先移除
+func prependMethod(methods []abi.Method, method abi.Method) []abi.Method {
+	res := []abi.Method{method}
+	for _, item := range methods {
+		if item.Sig != method.Sig || !sameNames(item.Inputs, method.Inputs) {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// prependEvent 把 event 放到最前面，已有完全相同的定义时先移除
+func prependEvent(events []abi.Event, event abi.Event) []abi.Event {
+	res := []abi.Event{event}
+	for _, item := range events {
+		if item.Sig != event.Sig || !sameNames(item.Inputs, event.Inputs) {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// sameNames 判断两组参数的名称和 indexed 是否完全相同
+func sameNames(a, b abi.Arguments) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Indexed != b[i].Indexed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package signatures
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func selectorOf(signature string) [4]byte {
+	var selector [4]byte
+	copy(selector[:], crypto.Keccak256([]byte(signature)))
+	return selector
+}
+
+func TestDefault_Methods(t *testing.T) {
+	signatures := []string{
+		"transfer(address,uint256)",
+		"safeTransferFrom(address,address,uint256,uint256,bytes)",
+		"setApprovalForAll(address,bool)",
+		"deposit()",
+		"withdraw(uint256)",
+		"swapExactTokensForTokens(uint256,uint256,address[],address,uint256)",
+		"exactInputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))",
+		"multicall(uint256,bytes[])",
+		"aggregate3((address,bool,bytes)[])",
+		"redeem(uint256,address,address)",
+		"permit(address,address,uint256,uint256,uint8,bytes32,bytes32)",
+		"transferOwnership(address)",
+	}
+	for _, signature := range signatures {
+		method, ok := Default().Method(selectorOf(signature))
+		if !ok || method.Sig != signature {
+			t.Errorf("Method(%s) = %s, %v", signature, method.Sig, ok)
+		}
+	}
+	if _, ok := Default().Method([4]byte{0xde, 0xad, 0xbe, 0xef}); ok {
+		t.Errorf("Method(0xdeadbeef) found, want not found")
+	}
+	// ERC20 与 ERC721 的 approve 选择器相同，两个定义都保留
+	if methods := Default().Methods(selectorOf("approve(address,uint256)")); len(methods) != 2 {
+		t.Errorf("Methods(approve) returned %d definitions, want 2", len(methods))
+	}
+}
+
+func TestNewRegistryWithFallback(t *testing.T) {
+	r := NewRegistryWithFallback(Default())
+	if method, ok := r.Method(selectorOf("transfer(address,uint256)")); !ok || method.Sig != "transfer(address,uint256)" {
+		t.Errorf("Method(transfer) = %s, %v, want found in fallback", method.Sig, ok)
+	}
+	err := r.RegisterJSON(`[{"inputs":[{"name":"guy","type":"address"},{"name":"wad","type":"uint256"}],"name":"approve","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"name":"amount","type":"uint256"}],"name":"stake","outputs":[],"stateMutability":"nonpayable","type":"function"}]`)
+	if err != nil {
+		t.Fatalf("RegisterJSON() error = %v", err)
+	}
+	if _, ok := r.Method(selectorOf("stake(uint256)")); !ok {
+		t.Errorf("Method(stake) not found after RegisterJSON")
+	}
+	if _, ok := Default().Method(selectorOf("stake(uint256)")); ok {
+		t.Errorf("Method(stake) found in Default, registering must not change the fallback")
+	}
+	// 新注册的 approve 排在最前，fallback 中的两个定义保留在后面
+	methods := r.Methods(selectorOf("approve(address,uint256)"))
+	if len(methods) != 3 || methods[0].Inputs[0].Name != "guy" {
+		t.Errorf("Methods(approve) = %d definitions, want 3 with the registered one first", len(methods))
+	}
+}
+
+func TestRegistry_DecodeLog(t *testing.T) {
+	from := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	to := common.HexToAddress("0xE837C72A310F201AD2E3CA4E44C1DD0D41F4EC8B")
+	topic := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	tests := []struct {
+		name      string
+		log       types.Log
+		wantValue string
+		want      *big.Int
+	}{
+		{
+			name:      "ERC20",
+			log:       types.Log{Topics: []common.Hash{topic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())}, Data: common.LeftPadBytes([]byte{100}, 32)},
+			wantValue: "value",
+			want:      big.NewInt(100),
+		},
+		{
+			name:      "ERC721",
+			log:       types.Log{Topics: []common.Hash{topic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()), common.BigToHash(big.NewInt(7))}},
+			wantValue: "tokenId",
+			want:      big.NewInt(7),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, values, err := Default().DecodeLog(tt.log)
+			if err != nil {
+				t.Fatalf("DecodeLog() error = %v", err)
+			}
+			if event.Name != "Transfer" || values["from"] != from || values["to"] != to || values[tt.wantValue].(*big.Int).Cmp(tt.want) != 0 {
+				t.Errorf("DecodeLog() = %s %v", event.Name, values)
+			}
+		})
+	}
+	unknown := types.Log{Topics: []common.Hash{crypto.Keccak256Hash([]byte("Unknown()"))}}
+	if _, _, err := Default().DecodeLog(unknown); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("DecodeLog() error = %v, want ErrUnknownEvent", err)
+	}
+}
+
+func TestRegistry_LoadDir(t *testing.T) {
+	r := NewRegistry()
+	if err := r.LoadDir("testdata"); err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	stake := selectorOf("stake(uint256,bool)")
+	if signature, ok := r.MethodSignature(append(stake[:], 0x01)); !ok || signature != "stake(uint256,bool)" {
+		t.Errorf("MethodSignature() = %s, %v, want stake(uint256,bool)", signature, ok)
+	}
+	if event, ok := r.Event(crypto.Keccak256Hash([]byte("Staked(address,uint256)"))); !ok || event.Name != "Staked" {
+		t.Errorf("Event(Staked) = %s, %v", event.Name, ok)
+	}
+	if err := r.LoadFile("testdata/missing.json"); err == nil {
+		t.Errorf("LoadFile() with missing file error = nil, want error")
+	}
+}
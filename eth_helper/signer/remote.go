@@ -0,0 +1,215 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+)
+
+var _ eth_interface.SignerInterface = (*RemoteSigner)(nil)
+
+// Protocol 远程签名服务使用的 JSON-RPC 协议
+type Protocol int
+
+const (
+	// ProtocolClef Clef 外部签名协议，使用 account_signTransaction、account_signData、account_signTypedData
+	ProtocolClef Protocol = iota
+	// ProtocolWeb3Signer web3signer 协议，使用 eth_signTransaction、eth_sign、eth_signTypedData
+	ProtocolWeb3Signer
+)
+
+// DefaultRemoteTimeout 远程签名请求的默认超时时间
+const DefaultRemoteTimeout = 30 * time.Second
+
+// RemoteOptions 远程签名服务的连接参数
+type RemoteOptions struct {
+	Protocol  Protocol
+	Timeout   time.Duration // 单次签名请求的超时时间，默认 DefaultRemoteTimeout
+	TLSConfig *tls.Config   // 配置客户端证书即可启用 mTLS，可以使用 NewMTLSConfig 创建
+}
+
+// RemoteSigner 把签名请求转发给外部签名服务，应用进程中不保存私钥
+type RemoteSigner struct {
+	address common.Address
+	opts    RemoteOptions
+	client  *rpc.Client
+}
+
+// signTransactionResult account_signTransaction 的返回结果
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// NewRemoteSigner 连接远程签名服务，address 为签名服务中管理的账户地址
+func NewRemoteSigner(ctx context.Context, url string, address common.Address, opts RemoteOptions) (*RemoteSigner, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultRemoteTimeout
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = opts.TLSConfig
+	client, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(&http.Client{Transport: transport, Timeout: opts.Timeout}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect remote signer: %v", err)
+	}
+	return &RemoteSigner{address: address, opts: opts, client: client}, nil
+}
+
+// NewMTLSConfig 读取客户端证书、私钥和签名服务的 CA 证书，创建 mTLS 配置
+// caFile 为空时使用系统根证书校验签名服务
+func NewMTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %v", err)
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("failed to parse CA certificate")
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// Close 关闭与签名服务的连接
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx 把交易发送给签名服务签名，并校验返回交易的内容和签名地址
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := s.txArgs(tx, chainID)
+	var raw hexutil.Bytes
+	switch s.opts.Protocol {
+	case ProtocolWeb3Signer:
+		if err := s.call(ctx, &raw, "eth_signTransaction", args); err != nil {
+			return nil, err
+		}
+	default:
+		var result signTransactionResult
+		if err := s.call(ctx, &result, "account_signTransaction", args); err != nil {
+			return nil, err
+		}
+		raw = result.Raw
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %v", err)
+	}
+	// 签名服务返回的交易必须与请求签名的交易一致
+	unsigned := types.LatestSignerForChainID(chainID)
+	if unsigned.Hash(signed) != unsigned.Hash(tx) {
+		return nil, errors.New("remote signer returned a different transaction")
+	}
+	sender, err := types.Sender(unsigned, signed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender: %v", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s, want %s", sender.Hex(), s.address.Hex())
+	}
+	// 签名服务返回的 blob 交易不带 sidecar，sidecar 不参与签名，直接放回原交易的 sidecar
+	if sidecar := tx.BlobTxSidecar(); sidecar != nil && signed.BlobTxSidecar() == nil {
+		signed = signed.WithBlobTxSidecar(sidecar)
+	}
+	return signed, nil
+}
+
+// SignMessage 按 eth_sign（personal_sign）规则签名消息，返回 V 为 27 或 28 的签名
+func (s *RemoteSigner) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	var err error
+	switch s.opts.Protocol {
+	case ProtocolWeb3Signer:
+		err = s.call(ctx, &signature, "eth_sign", s.address, hexutil.Bytes(message))
+	default:
+		err = s.call(ctx, &signature, "account_signData", "text/plain", common.NewMixedcaseAddress(s.address), hexutil.Bytes(message))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
+func (s *RemoteSigner) SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+	var signature hexutil.Bytes
+	var err error
+	switch s.opts.Protocol {
+	case ProtocolWeb3Signer:
+		err = s.call(ctx, &signature, "eth_signTypedData", s.address, typedData)
+	default:
+		err = s.call(ctx, &signature, "account_signTypedData", common.NewMixedcaseAddress(s.address), typedData)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
+// call 按超时时间发送一次签名请求
+func (s *RemoteSigner) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+	if err := s.client.CallContext(ctx, result, method, args...); err != nil {
+		return fmt.Errorf("remote signer %s: %w", method, err)
+	}
+	return nil
+}
+
+// txArgs 把交易转换为签名服务使用的请求参数
+func (s *RemoteSigner) txArgs(tx *types.Transaction, chainID *big.Int) apitypes.SendTxArgs {
+	data := hexutil.Bytes(tx.Data())
+	args := apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Input:   &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.AccessListTxType:
+		accessList := tx.AccessList()
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+		args.AccessList = &accessList
+	default:
+		accessList := tx.AccessList()
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		args.AccessList = &accessList
+		if tx.Type() == types.BlobTxType {
+			args.BlobFeeCap = (*hexutil.Big)(tx.BlobGasFeeCap())
+			args.BlobHashes = tx.BlobHashes()
+		}
+	}
+	return args
+}
@@ -0,0 +1,206 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/holiman/uint256"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+	"github.com/web3coderecho/web3_helper/eth_helper/signer/signertest"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testPrivateKey)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	server := signertest.NewServer(key)
+	defer server.Close()
+	chainID := big.NewInt(1)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	txs := []types.TxData{
+		&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)},
+		&types.DynamicFeeTx{ChainID: chainID, Nonce: 2, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, To: &to, Data: []byte{1}},
+	}
+	for _, protocol := range []Protocol{ProtocolClef, ProtocolWeb3Signer} {
+		s, err := NewRemoteSigner(context.Background(), server.URL, address, RemoteOptions{Protocol: protocol})
+		if err != nil {
+			t.Fatalf("NewRemoteSigner() error = %v", err)
+		}
+		for _, txData := range txs {
+			tx := types.NewTx(txData)
+			signed, err := s.SignTx(context.Background(), tx, chainID)
+			if err != nil {
+				t.Fatalf("protocol %d: SignTx() error = %v", protocol, err)
+			}
+			sender, _ := types.Sender(types.LatestSignerForChainID(chainID), signed)
+			if sender != address || signed.Type() != tx.Type() {
+				t.Errorf("protocol %d: SignTx() sender = %s type = %d, want %s type %d", protocol, sender, signed.Type(), address, tx.Type())
+			}
+		}
+		message := []byte("hello")
+		signature, err := s.SignMessage(context.Background(), message)
+		if err != nil {
+			t.Fatalf("protocol %d: SignMessage() error = %v", protocol, err)
+		}
+		signature[64] -= 27
+		pub, err := crypto.SigToPub(accounts.TextHash(message), signature)
+		if err != nil || crypto.PubkeyToAddress(*pub) != address {
+			t.Errorf("protocol %d: SignMessage() recovered a different address", protocol)
+		}
+		if _, ok := interface{}(s).(eth_interface.HashSigner); ok {
+			t.Errorf("protocol %d: RemoteSigner implements HashSigner, raw hashes are not supported", protocol)
+		}
+		s.Close()
+	}
+
+	// 签名服务不管理的账户
+	other := common.HexToAddress("0x595C4A379AB80C202F0372BBF9BBF3FAD6CA8768")
+	s, _ := NewRemoteSigner(context.Background(), server.URL, other, RemoteOptions{})
+	defer s.Close()
+	if _, err := s.SignTx(context.Background(), types.NewTx(txs[0]), chainID); err == nil {
+		t.Errorf("SignTx() with unknown account error = nil")
+	}
+}
+
+func TestRemoteSigner_BlobTx(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testPrivateKey)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	server := signertest.NewServer(key)
+	defer server.Close()
+	chainID := big.NewInt(1)
+	sidecar := &types.BlobTxSidecar{
+		Blobs:       []kzg4844.Blob{{1}},
+		Commitments: []kzg4844.Commitment{{2}},
+		Proofs:      []kzg4844.Proof{{3}},
+	}
+	tx := types.NewTx(&types.BlobTx{
+		ChainID:    uint256.NewInt(1),
+		Nonce:      3,
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.NewInt(2),
+		Gas:        21000,
+		To:         common.HexToAddress("0x000000000000000000000000000000000000dEaD"),
+		BlobFeeCap: uint256.NewInt(3),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	})
+	for _, protocol := range []Protocol{ProtocolClef, ProtocolWeb3Signer} {
+		s, err := NewRemoteSigner(context.Background(), server.URL, address, RemoteOptions{Protocol: protocol})
+		if err != nil {
+			t.Fatalf("NewRemoteSigner() error = %v", err)
+		}
+		signed, err := s.SignTx(context.Background(), tx, chainID)
+		s.Close()
+		if err != nil {
+			t.Fatalf("protocol %d: SignTx() error = %v", protocol, err)
+		}
+		if sender, _ := types.Sender(types.NewCancunSigner(chainID), signed); sender != address {
+			t.Errorf("protocol %d: SignTx() sender = %s, want %s", protocol, sender, address)
+		}
+		if signed.BlobTxSidecar() == nil || signed.BlobTxSidecar().Blobs[0] != sidecar.Blobs[0] {
+			t.Errorf("protocol %d: SignTx() dropped the blob sidecar", protocol)
+		}
+	}
+}
+
+func TestRemoteSigner_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+	s, err := NewRemoteSigner(context.Background(), server.URL, common.Address{}, RemoteOptions{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewRemoteSigner() error = %v", err)
+	}
+	defer s.Close()
+	start := time.Now()
+	if _, err = s.SignMessage(context.Background(), []byte("hello")); err == nil {
+		t.Fatalf("SignMessage() error = nil, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("SignMessage() returned after %v, want about 50ms", elapsed)
+	}
+}
+
+func TestRemoteSigner_MTLS(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testPrivateKey)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	caCert := newCertificate(t, nil)
+	clientCert := newCertificate(t, caCert)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(caCert.Leaf)
+	server := signertest.NewTLSServer(key, &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs})
+	defer server.Close()
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	tests := []struct {
+		name    string
+		certs   []tls.Certificate
+		wantErr bool
+	}{
+		{"with client certificate", []tls.Certificate{*clientCert}, false},
+		{"without client certificate", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewRemoteSigner(context.Background(), server.URL, address, RemoteOptions{
+				TLSConfig: &tls.Config{Certificates: tt.certs, RootCAs: rootCAs},
+			})
+			if err != nil {
+				t.Fatalf("NewRemoteSigner() error = %v", err)
+			}
+			defer s.Close()
+			_, err = s.SignMessage(context.Background(), []byte("hello"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SignMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newCertificate 生成测试证书，parent 为 nil 时生成自签名 CA
+func newCertificate(t *testing.T, parent *tls.Certificate) *tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "signertest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	issuer, signKey := template, key
+	if parent != nil {
+		issuer, signKey = parent.Leaf, parent.PrivateKey.(*ecdsa.PrivateKey)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
@@ -0,0 +1,98 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+	"github.com/web3coderecho/web3_helper/utils/hdwallet"
+)
+
+var _ eth_interface.HashSigner = (*PrivateKeySigner)(nil)
+
+// PrivateKeySigner 使用内存中的私钥签名
+type PrivateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewPrivateKeySigner 使用私钥创建签名器
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// NewPrivateKeySignerFromHex 使用十六进制私钥创建签名器，允许带 0x 前缀
+func NewPrivateKeySignerFromHex(privateKey string) (*PrivateKeySigner, error) {
+	key, err := crypto.HexToECDSA(trimHexPrefix(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert private key: %v", err)
+	}
+	return NewPrivateKeySigner(key), nil
+}
+
+// NewKeystoreSigner 使用 keystore V3 JSON 和密码创建签名器
+func NewKeystoreSigner(keystoreJSON []byte, password string) (*PrivateKeySigner, error) {
+	key, err := keystore.DecryptKey(keystoreJSON, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %v", err)
+	}
+	return NewPrivateKeySigner(key.PrivateKey), nil
+}
+
+// NewKeystoreFileSigner 读取 keystore 文件并使用密码创建签名器
+func NewKeystoreFileSigner(path, password string) (*PrivateKeySigner, error) {
+	keystoreJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %v", err)
+	}
+	return NewKeystoreSigner(keystoreJSON, password)
+}
+
+// NewHDWalletSigner 使用 HD 钱包 m/44'/60'/account'/change/index 路径派生的私钥创建签名器
+func NewHDWalletSigner(wallet *hdwallet.HDWallet, account, change, index uint32) (*PrivateKeySigner, error) {
+	info, err := wallet.GenETHByIndex(account, change, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %v", err)
+	}
+	return NewPrivateKeySigner(info.PrivateKey), nil
+}
+
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *PrivateKeySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *PrivateKeySigner) SignHash(_ context.Context, hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key)
+}
+
+func (s *PrivateKeySigner) SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %v", err)
+	}
+	signature, err := s.SignHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+func trimHexPrefix(s string) string {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:]
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/web3coderecho/web3_helper/utils/hdwallet"
+)
+
+const testPrivateKey = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+
+func TestPrivateKeySigner_SignTx(t *testing.T) {
+	s, err := NewPrivateKeySignerFromHex("0x" + testPrivateKey)
+	if err != nil {
+		t.Fatalf("NewPrivateKeySignerFromHex() error = %v", err)
+	}
+	chainID := big.NewInt(1)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	tests := []struct {
+		name string
+		tx   types.TxData
+	}{
+		{"legacy", &types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)}},
+		{"dynamic fee", &types.DynamicFeeTx{ChainID: chainID, Nonce: 1, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, To: &to}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := s.SignTx(context.Background(), types.NewTx(tt.tx), chainID)
+			if err != nil {
+				t.Fatalf("SignTx() error = %v", err)
+			}
+			sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+			if err != nil || sender != s.Address() {
+				t.Errorf("SignTx() sender = %s, %v, want %s", sender, err, s.Address())
+			}
+		})
+	}
+}
+
+func TestSigners_Address(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testPrivateKey)
+	want := crypto.PubkeyToAddress(key.PublicKey)
+	keystoreJSON, err := keystore.EncryptKey(&keystore.Key{Address: want, PrivateKey: key}, "123456", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("EncryptKey() error = %v", err)
+	}
+	keystoreSigner, err := NewKeystoreSigner(keystoreJSON, "123456")
+	if err != nil {
+		t.Fatalf("NewKeystoreSigner() error = %v", err)
+	}
+	if keystoreSigner.Address() != want {
+		t.Errorf("NewKeystoreSigner() address = %s, want %s", keystoreSigner.Address(), want)
+	}
+	if _, err = NewKeystoreSigner(keystoreJSON, "wrong"); err == nil {
+		t.Errorf("NewKeystoreSigner() with wrong password error = nil")
+	}
+
+	wallet, err := hdwallet.NewHDWallet(128)
+	if err != nil {
+		t.Fatalf("NewHDWallet() error = %v", err)
+	}
+	info, _ := wallet.GenETHByIndex(0, 0, 3)
+	hdSigner, err := NewHDWalletSigner(wallet, 0, 0, 3)
+	if err != nil || hdSigner.Address() != info.Address {
+		t.Errorf("NewHDWalletSigner() = %v, %v, want %s", hdSigner, err, info.Address)
+	}
+}
+
+func TestPrivateKeySigner_SignHash(t *testing.T) {
+	s, _ := NewPrivateKeySignerFromHex(testPrivateKey)
+	hash := crypto.Keccak256([]byte("hello"))
+	signature, err := s.SignHash(context.Background(), hash)
+	if err != nil {
+		t.Fatalf("SignHash() error = %v", err)
+	}
+	pub, err := crypto.SigToPub(hash, signature)
+	if err != nil || crypto.PubkeyToAddress(*pub) != s.Address() {
+		t.Errorf("SignHash() recovered %v, %v, want %s", pub, err, s.Address())
+	}
+}
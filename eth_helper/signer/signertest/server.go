@@ -0,0 +1,156 @@
+// Package signertest 提供离线测试使用的远程签名服务
+// 同时实现 Clef（account_ 命名空间）和 web3signer（eth_ 命名空间）的签名接口
+package signertest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ErrUnknownAccount 请求签名的账户不由该服务管理
+var ErrUnknownAccount = errors.New("unknown account")
+
+// keyStore 签名服务管理的私钥
+type keyStore struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func (k *keyStore) check(address common.Address) error {
+	if address != k.address {
+		return fmt.Errorf("%w: %s", ErrUnknownAccount, address.Hex())
+	}
+	return nil
+}
+
+func (k *keyStore) signTx(args apitypes.SendTxArgs) (hexutil.Bytes, *types.Transaction, error) {
+	if err := k.check(args.From.Address()); err != nil {
+		return nil, nil, err
+	}
+	if args.ChainID == nil {
+		return nil, nil, errors.New("chainId is required")
+	}
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, nil, err
+	}
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), k.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	return raw, signed, err
+}
+
+// signText 按 personal_sign 规则签名，V 为 27 或 28
+func (k *keyStore) signText(address common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if err := k.check(address); err != nil {
+		return nil, err
+	}
+	return k.sign(accounts.TextHash(data))
+}
+
+func (k *keyStore) signTypedData(address common.Address, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	if err := k.check(address); err != nil {
+		return nil, err
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	return k.sign(hash)
+}
+
+func (k *keyStore) sign(hash []byte) (hexutil.Bytes, error) {
+	signature, err := crypto.Sign(hash, k.key)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// clefAPI Clef 外部签名接口
+type clefAPI struct {
+	keys *keyStore
+}
+
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func (api *clefAPI) SignTransaction(_ context.Context, args apitypes.SendTxArgs, _ *string) (*signTransactionResult, error) {
+	raw, tx, err := api.keys.signTx(args)
+	if err != nil {
+		return nil, err
+	}
+	return &signTransactionResult{Raw: raw, Tx: tx}, nil
+}
+
+func (api *clefAPI) SignData(_ context.Context, contentType string, address common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if contentType != "text/plain" {
+		return nil, fmt.Errorf("unsupported content type %s", contentType)
+	}
+	return api.keys.signText(address.Address(), data)
+}
+
+func (api *clefAPI) SignTypedData(_ context.Context, address common.MixedcaseAddress, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	return api.keys.signTypedData(address.Address(), typedData)
+}
+
+// web3SignerAPI web3signer 签名接口
+type web3SignerAPI struct {
+	keys *keyStore
+}
+
+func (api *web3SignerAPI) SignTransaction(_ context.Context, args apitypes.SendTxArgs) (hexutil.Bytes, error) {
+	raw, _, err := api.keys.signTx(args)
+	return raw, err
+}
+
+func (api *web3SignerAPI) Sign(_ context.Context, address common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	return api.keys.signText(address, data)
+}
+
+func (api *web3SignerAPI) SignTypedData(_ context.Context, address common.Address, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	return api.keys.signTypedData(address, typedData)
+}
+
+// NewHandler 创建使用 key 签名的 JSON-RPC 服务
+func NewHandler(key *ecdsa.PrivateKey) *rpc.Server {
+	keys := &keyStore{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &clefAPI{keys: keys}); err != nil {
+		panic(err)
+	}
+	if err := server.RegisterName("eth", &web3SignerAPI{keys: keys}); err != nil {
+		panic(err)
+	}
+	return server
+}
+
+// NewServer 启动 HTTP 签名服务，使用完毕后需要调用 Close
+func NewServer(key *ecdsa.PrivateKey) *httptest.Server {
+	return httptest.NewServer(NewHandler(key))
+}
+
+// NewTLSServer 启动 HTTPS 签名服务，tlsConfig 中配置 ClientAuth 和 ClientCAs 即可要求客户端证书
+func NewTLSServer(key *ecdsa.PrivateKey, tlsConfig *tls.Config) *httptest.Server {
+	server := httptest.NewUnstartedServer(NewHandler(key))
+	server.TLS = tlsConfig
+	server.StartTLS()
+	return server
+}
@@ -0,0 +1,25 @@
+package eth_helper
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+)
+
+// ErrMissingSigner TxOptions 没有设置 Signer
+var ErrMissingSigner = errors.New("transaction options: signer is required")
+
+// TxOptions 单笔交易的参数，除 Signer 外零值表示使用默认值
+type TxOptions struct {
+	Signer         eth_interface.SignerInterface // 交易签名器，发送地址为 Signer.Address()
+	GasLimit       uint64                        // 大于估算值时使用 GasLimit，否则使用估算值
+	GasPrice       *big.Int                      // 传统交易的 gasPrice，EIP-1559 交易的 maxFeePerGas
+	GasTipCap      *big.Int                      // EIP-1559 交易的 maxPriorityFeePerGas，传统交易忽略
+	Nonce          *uint64                       // 为 nil 时由 NonceManager 分配
+	TxType         TxType                        // 交易类型，TxTypeAuto 表示使用 SetTxType 设置的类型
+	AccessList     types.AccessList              // 交易携带的访问列表，传统交易会改为 AccessListTx 发送
+	AutoAccessList bool                          // AccessList 为空时通过 eth_createAccessList 生成，只在能节省 gas 时使用，节点不支持时忽略
+	BlobGasFeeCap  *big.Int                      // blob 交易的 maxFeePerBlobGas，为 nil 时使用 SuggestBlobGasFeeCap
+}
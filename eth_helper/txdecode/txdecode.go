@@ -0,0 +1,355 @@
+package txdecode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+	"github.com/web3coderecho/web3_helper/eth_helper/revert"
+	"github.com/web3coderecho/web3_helper/eth_helper/signatures"
+	"github.com/web3coderecho/web3_helper/utils"
+)
+
+var (
+	// ErrUnknownSelector 已知 ABI 中没有与调用数据匹配的方法
+	ErrUnknownSelector = errors.New("unknown function selector")
+	// ErrInvalidTransaction 原始交易无法解码
+	ErrInvalidTransaction = errors.New("invalid raw transaction")
+)
+
+// txTypeNames 交易类型名称
+var txTypeNames = map[uint8]string{
+	types.LegacyTxType:     "legacy",
+	types.AccessListTxType: "access list (EIP-2930)",
+	types.DynamicFeeTxType: "dynamic fee (EIP-1559)",
+	types.BlobTxType:       "blob (EIP-4844)",
+	types.SetCodeTxType:    "set code (EIP-7702)",
+}
+
+// Tx 解码后的交易
+type Tx struct {
+	Hash          common.Hash
+	Type          uint8
+	ChainID       *big.Int
+	From          common.Address  // 从签名恢复的发送地址
+	To            *common.Address // nil 表示创建合约
+	Value         decimal.Decimal // 转账金额，单位 ETH
+	Nonce         uint64
+	Gas           uint64
+	GasPrice      *big.Int // 传统交易的 gasPrice，其他类型为 maxFeePerGas
+	GasTipCap     *big.Int // maxPriorityFeePerGas
+	GasFeeCap     *big.Int // maxFeePerGas
+	BlobGasFeeCap *big.Int // maxFeePerBlobGas，仅 blob 交易
+	BlobHashes    []common.Hash
+	AccessList    types.AccessList
+	MaxFee        decimal.Decimal // 最多支付的手续费（包含 blob 手续费），单位 ETH
+	Data          []byte
+	Call          *Call // 调用数据的解码结果，没有调用数据或无法识别时为 nil
+	Transaction   *types.Transaction
+}
+
+// Arg 解码后的方法参数
+type Arg struct {
+	Name      string
+	Type      string
+	Value     interface{}
+	Formatted string // 可读的参数值，ERC20 金额按精度换算并带上代币符号
+}
+
+// Call 解码后的合约调用
+type Call struct {
+	Selector  [4]byte
+	Method    string // 方法名称，如 transfer
+	Signature string // 方法签名，如 transfer(address,uint256)
+	Args      []Arg
+}
+
+// String 格式化为 transfer(to=0x..., value=12.5 USDT)
+func (c *Call) String() string {
+	parts := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		parts[i] = arg.Name + "=" + arg.Formatted
+	}
+	return fmt.Sprintf("%s(%s)", c.Method, strings.Join(parts, ", "))
+}
+
+// TypeName 交易类型名称
+func (t *Tx) TypeName() string {
+	if name, ok := txTypeNames[t.Type]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown (0x%x)", t.Type)
+}
+
+// String 多行格式化交易内容
+func (t *Tx) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "hash:     %s\n", t.Hash.Hex())
+	fmt.Fprintf(&b, "type:     %s\n", t.TypeName())
+	fmt.Fprintf(&b, "chainId:  %s\n", t.ChainID)
+	fmt.Fprintf(&b, "from:     %s\n", t.From.Hex())
+	if t.To == nil {
+		fmt.Fprintf(&b, "to:       (contract creation)\n")
+	} else {
+		fmt.Fprintf(&b, "to:       %s\n", t.To.Hex())
+	}
+	fmt.Fprintf(&b, "value:    %s ETH\n", t.Value)
+	fmt.Fprintf(&b, "nonce:    %d\n", t.Nonce)
+	fmt.Fprintf(&b, "gas:      %d\n", t.Gas)
+	if t.Type == types.LegacyTxType || t.Type == types.AccessListTxType {
+		fmt.Fprintf(&b, "gasPrice: %s wei\n", t.GasPrice)
+	} else {
+		fmt.Fprintf(&b, "maxFeePerGas:         %s wei\n", t.GasFeeCap)
+		fmt.Fprintf(&b, "maxPriorityFeePerGas: %s wei\n", t.GasTipCap)
+	}
+	if t.BlobGasFeeCap != nil {
+		fmt.Fprintf(&b, "maxFeePerBlobGas:     %s wei\n", t.BlobGasFeeCap)
+		fmt.Fprintf(&b, "blobs:    %d\n", len(t.BlobHashes))
+	}
+	fmt.Fprintf(&b, "maxFee:   %s ETH\n", t.MaxFee)
+	switch {
+	case t.Call != nil:
+		fmt.Fprintf(&b, "call:     %s\n", t.Call)
+	case len(t.Data) > 0:
+		fmt.Fprintf(&b, "data:     %s\n", hexutil.Encode(t.Data))
+	}
+	return b.String()
+}
+
+// method 待解码的方法，erc20 表示按代币精度格式化金额
+type method struct {
+	abi.Method
+	erc20 bool
+}
+
+// token 代币信息，ok 为 false 表示不是 ERC20 代币
+type token struct {
+	decimals int
+	symbol   string
+	ok       bool
+}
+
+// Decoder 解码原始交易和调用数据，方法定义来自 signatures.Registry
+type Decoder struct {
+	eth      *eth_helper.EthHelper
+	registry *signatures.Registry
+	erc20    *abi.ABI
+	mu       sync.RWMutex
+	tokens   map[common.Address]token
+}
+
+// NewDecoder 创建 Decoder，eth 用于查询 ERC20 代币的精度和符号，为 nil 时金额不换算
+// Decoder 使用自己的注册表，找不到时再查默认注册表，Register 不影响其他解码器
+func NewDecoder(eth *eth_helper.EthHelper) *Decoder {
+	return NewDecoderWithRegistry(eth, signatures.NewRegistryWithFallback(signatures.Default()))
+}
+
+// NewDecoderWithRegistry 使用指定的签名注册表创建 Decoder
+func NewDecoderWithRegistry(eth *eth_helper.EthHelper, registry *signatures.Registry) *Decoder {
+	erc20ABI, _ := erc20.Erc20MetaData.GetAbi()
+	return &Decoder{
+		eth:      eth,
+		registry: registry,
+		erc20:    erc20ABI,
+		tokens:   make(map[common.Address]token),
+	}
+}
+
+// Registry 返回 Decoder 使用的签名注册表
+func (d *Decoder) Registry() *signatures.Registry {
+	return d.registry
+}
+
+// Register 在签名注册表中注册 ABI 中的方法，选择器冲突时优先使用后注册的方法
+func (d *Decoder) Register(contractABI *abi.ABI) {
+	d.registry.Register(contractABI)
+}
+
+// RegisterJSON 解析 JSON ABI 并注册其中的方法
+func (d *Decoder) RegisterJSON(abiJSON string) error {
+	return d.registry.RegisterJSON(abiJSON)
+}
+
+// DecodeRaw 解码 0x 开头的原始交易（eth_sendRawTransaction 的参数），支持 legacy、2930、1559 和 4844 交易
+func (d *Decoder) DecodeRaw(ctx context.Context, rawHex string) (*Tx, error) {
+	raw, err := hexutil.Decode(rawHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	return d.DecodeTx(ctx, tx)
+}
+
+// DecodeTx 解码已签名的交易，调用数据无法识别时 Call 为 nil
+func (d *Decoder) DecodeTx(ctx context.Context, tx *types.Transaction) (*Tx, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to recover sender: %v", ErrInvalidTransaction, err)
+	}
+	fee := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+	if tx.Type() == types.BlobTxType {
+		fee.Add(fee, new(big.Int).Mul(tx.BlobGasFeeCap(), new(big.Int).SetUint64(tx.BlobGas())))
+	}
+	res := &Tx{
+		Hash:        tx.Hash(),
+		Type:        tx.Type(),
+		ChainID:     tx.ChainId(),
+		From:        from,
+		To:          tx.To(),
+		Value:       utils.FromEther(tx.Value()),
+		Nonce:       tx.Nonce(),
+		Gas:         tx.Gas(),
+		GasPrice:    tx.GasPrice(),
+		GasTipCap:   tx.GasTipCap(),
+		GasFeeCap:   tx.GasFeeCap(),
+		BlobHashes:  tx.BlobHashes(),
+		AccessList:  tx.AccessList(),
+		MaxFee:      utils.FromEther(fee),
+		Data:        tx.Data(),
+		Transaction: tx,
+	}
+	if tx.Type() == types.BlobTxType {
+		res.BlobGasFeeCap = tx.BlobGasFeeCap()
+	}
+	if tx.To() != nil && len(tx.Data()) >= 4 {
+		if call, err := d.DecodeCalldata(ctx, tx.To(), tx.Data()); err == nil {
+			res.Call = call
+		}
+	}
+	return res, nil
+}
+
+// DecodeCalldata 按已知 ABI 解码调用数据，to 为调用的合约地址，用于识别 ERC20 代币并换算金额
+func (d *Decoder) DecodeCalldata(ctx context.Context, to *common.Address, data []byte) (*Call, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("%w: calldata too short", ErrUnknownSelector)
+	}
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	// ERC20 与 ERC721 的 approve、transferFrom 选择器相同，能查询到精度的合约优先按 ERC20 解码并换算金额
+	var (
+		info    token
+		ordered []method
+	)
+	if item, err := d.erc20.MethodById(selector[:]); err == nil && to != nil {
+		if info = d.token(ctx, *to); info.ok {
+			ordered = append(ordered, method{Method: *item, erc20: true})
+		}
+	}
+	for _, item := range d.registry.Methods(selector) {
+		ordered = append(ordered, method{Method: item})
+	}
+	if len(ordered) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrUnknownSelector, hexutil.Encode(selector[:]))
+	}
+	var err error
+	for _, candidate := range ordered {
+		var call *Call
+		if call, err = decodeMethod(candidate, data, info); err == nil {
+			return call, nil
+		}
+	}
+	return nil, err
+}
+
+// token 查询并缓存合约的 ERC20 精度和符号
+func (d *Decoder) token(ctx context.Context, address common.Address) token {
+	if d.eth == nil {
+		return token{}
+	}
+	d.mu.RLock()
+	info, ok := d.tokens[address]
+	d.mu.RUnlock()
+	if ok {
+		return info
+	}
+	erc := contract.NewErc20(d.eth, address)
+	decimals, err := erc.GetDecimals(ctx)
+	switch {
+	case err == nil:
+		info = token{decimals: decimals, ok: true}
+		info.symbol, _ = erc.GetSymbol(ctx)
+	case !isNotToken(err):
+		// 网络或节点错误不能说明合约不是代币，不缓存，下次重新查询
+		return info
+	}
+	d.mu.Lock()
+	d.tokens[address] = info
+	d.mu.Unlock()
+	return info
+}
+
+// isNotToken 判断 decimals 调用失败是否说明合约确定不是 ERC20：调用回滚、地址没有代码或返回值无法解码
+func isNotToken(err error) bool {
+	var revertErr *revert.RevertError
+	return errors.As(err, &revertErr) || errors.Is(err, bind.ErrNoCode) || strings.HasPrefix(err.Error(), "abi:")
+}
+
+// decodeMethod 解码方法参数，info.ok 为 true 时 ERC20 方法的 uint256 参数按代币精度换算
+func decodeMethod(m method, data []byte, info token) (*Call, error) {
+	values, err := m.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s: %v", m.Sig, err)
+	}
+	call := &Call{Method: m.RawName, Signature: m.Sig, Args: make([]Arg, len(values))}
+	copy(call.Selector[:], data[:4])
+	for i, value := range values {
+		input := m.Inputs[i]
+		name := input.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		formatted := FormatValue(value)
+		if amount, ok := value.(*big.Int); ok && m.erc20 && info.ok {
+			formatted = utils.FromWeiWithDecimals(amount, info.decimals).String()
+			if info.symbol != "" {
+				formatted += " " + info.symbol
+			}
+		}
+		call.Args[i] = Arg{Name: name, Type: input.Type.String(), Value: value, Formatted: formatted}
+	}
+	return call, nil
+}
+
+// FormatValue 格式化 ABI 解码出的值，地址使用校验和格式，字节使用 0x 开头的十六进制
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case []common.Address:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = item.Hex()
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case []*big.Int:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = item.String()
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case []byte:
+		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case string:
+		return fmt.Sprintf("%q", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,205 @@
+package txdecode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/web3coderecho/web3_helper/eth_helper"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc721"
+	"github.com/web3coderecho/web3_helper/eth_helper/rpctest"
+)
+
+var (
+	usdt      = common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	nft       = common.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D")
+	recipient = common.HexToAddress("0xE837C72A310F201AD2E3CA4E44C1DD0D41F4EC8B")
+)
+
+// newTokenServer 模拟节点，usdt 是精度为 6 的 USDT 代币，其他合约的调用都回滚
+func newTokenServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return rpctest.NewServer(t, rpctest.Handlers{"eth_call": tokenCall})
+}
+
+func tokenCall(params []json.RawMessage) (interface{}, error) {
+	erc20ABI, _ := erc20.Erc20MetaData.GetAbi()
+	args := rpctest.ParseCall(params)
+	if args.To == nil || *args.To != usdt {
+		return nil, rpctest.ErrReverted
+	}
+	var output []byte
+	switch string(args.Calldata()) {
+	case string(erc20ABI.Methods["decimals"].ID):
+		output, _ = erc20ABI.Methods["decimals"].Outputs.Pack(uint8(6))
+	case string(erc20ABI.Methods["symbol"].ID):
+		output, _ = erc20ABI.Methods["symbol"].Outputs.Pack("USDT")
+	default:
+		return nil, rpctest.ErrReverted
+	}
+	return hexutil.Bytes(output), nil
+}
+
+func signedRawTx(t *testing.T, txData types.TxData) string {
+	t.Helper()
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), txData)
+	if err != nil {
+		t.Fatalf("SignNewTx() error = %v", err)
+	}
+	raw, _ := tx.MarshalBinary()
+	return hexutil.Encode(raw)
+}
+
+func TestDecoder_DecodeRaw(t *testing.T) {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	erc20ABI, _ := erc20.Erc20MetaData.GetAbi()
+	erc721ABI, _ := erc721.Erc721MetaData.GetAbi()
+	transfer, _ := erc20ABI.Pack("transfer", recipient, big.NewInt(12500000))
+	approveNFT, _ := erc721ABI.Pack("approve", recipient, big.NewInt(5))
+	chainID := big.NewInt(1)
+	tests := []struct {
+		name     string
+		txData   types.TxData
+		wantType uint8
+		wantFee  string
+		wantCall string
+	}{
+		{
+			name:     "legacy ETH transfer",
+			txData:   &types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(2e9), Gas: 21000, To: &recipient, Value: big.NewInt(15e17)},
+			wantType: types.LegacyTxType,
+			wantFee:  "0.000042",
+		},
+		{
+			name:     "access list ERC721 approve",
+			txData:   &types.AccessListTx{ChainID: chainID, Nonce: 2, GasPrice: big.NewInt(1e9), Gas: 50000, To: &nft, Data: approveNFT},
+			wantType: types.AccessListTxType,
+			wantFee:  "0.00005",
+			wantCall: fmt.Sprintf("approve(to=%s, tokenId=5)", recipient.Hex()),
+		},
+		{
+			name:     "dynamic fee ERC20 transfer",
+			txData:   &types.DynamicFeeTx{ChainID: chainID, Nonce: 3, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(3e9), Gas: 60000, To: &usdt, Data: transfer},
+			wantType: types.DynamicFeeTxType,
+			wantFee:  "0.00018",
+			wantCall: fmt.Sprintf("transfer(to=%s, value=12.5 USDT)", recipient.Hex()),
+		},
+		{
+			name: "blob",
+			txData: &types.BlobTx{
+				ChainID: uint256.NewInt(1), Nonce: 4, GasTipCap: uint256.NewInt(1e9), GasFeeCap: uint256.NewInt(2e9), Gas: 21000, To: recipient,
+				BlobFeeCap: uint256.NewInt(1e9), BlobHashes: []common.Hash{{0x01}},
+			},
+			wantType: types.BlobTxType,
+			wantFee:  "0.000173072",
+		},
+	}
+	eth := eth_helper.NewEthHelper(newTokenServer(t).URL)
+	defer eth.Close()
+	decoder := NewDecoder(eth)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decoder.DecodeRaw(context.Background(), signedRawTx(t, tt.txData))
+			if err != nil {
+				t.Fatalf("DecodeRaw() error = %v", err)
+			}
+			if got.Type != tt.wantType || got.From != sender || got.MaxFee.String() != tt.wantFee {
+				t.Errorf("DecodeRaw() = type %d, from %s, maxFee %s, want type %d, from %s, maxFee %s", got.Type, got.From.Hex(), got.MaxFee, tt.wantType, sender.Hex(), tt.wantFee)
+			}
+			var call string
+			if got.Call != nil {
+				call = got.Call.String()
+			}
+			if call != tt.wantCall {
+				t.Errorf("DecodeRaw() call = %q, want %q", call, tt.wantCall)
+			}
+			if !strings.Contains(got.String(), got.TypeName()) {
+				t.Errorf("String() = %q, missing type", got.String())
+			}
+		})
+	}
+	if _, err := decoder.DecodeRaw(context.Background(), "0x02c0"); !errors.Is(err, ErrInvalidTransaction) {
+		t.Errorf("DecodeRaw() error = %v, want ErrInvalidTransaction", err)
+	}
+}
+
+func TestDecoder_RegisterJSON(t *testing.T) {
+	decoder := NewDecoder(nil)
+	data := append(crypto.Keccak256([]byte("stake(uint256,bool)"))[:4], common.LeftPadBytes([]byte{7}, 32)...)
+	data = append(data, common.LeftPadBytes([]byte{1}, 32)...)
+	if _, err := decoder.DecodeCalldata(context.Background(), &recipient, data); !errors.Is(err, ErrUnknownSelector) {
+		t.Fatalf("DecodeCalldata() error = %v, want ErrUnknownSelector", err)
+	}
+	err := decoder.RegisterJSON(`[{"inputs":[{"name":"amount","type":"uint256"},{"name":"lock","type":"bool"}],"name":"stake","outputs":[],"stateMutability":"nonpayable","type":"function"}]`)
+	if err != nil {
+		t.Fatalf("RegisterJSON() error = %v", err)
+	}
+	call, err := decoder.DecodeCalldata(context.Background(), &recipient, data)
+	if err != nil || call.String() != "stake(amount=7, lock=true)" {
+		t.Errorf("DecodeCalldata() = %v, %v, want stake(amount=7, lock=true)", call, err)
+	}
+	// 注册只对当前解码器生效
+	if _, err := NewDecoder(nil).DecodeCalldata(context.Background(), &recipient, data); !errors.Is(err, ErrUnknownSelector) {
+		t.Errorf("DecodeCalldata() with another decoder error = %v, want ErrUnknownSelector", err)
+	}
+}
+
+func TestDecoder_TokenCache(t *testing.T) {
+	erc20ABI, _ := erc20.Erc20MetaData.GetAbi()
+	transfer, _ := erc20ABI.Pack("transfer", recipient, big.NewInt(12500000))
+	other := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	var calls int
+	failing := true
+	server := rpctest.NewServer(t, rpctest.Handlers{
+		"eth_call": func(params []json.RawMessage) (interface{}, error) {
+			calls++
+			if failing {
+				return nil, errors.New("upstream unavailable")
+			}
+			return tokenCall(params)
+		},
+	})
+	eth := eth_helper.NewEthHelper(server.URL)
+	defer eth.Close()
+	decoder := NewDecoder(eth)
+	decode := func() string {
+		call, err := decoder.DecodeCalldata(context.Background(), &usdt, transfer)
+		if err != nil {
+			t.Fatalf("DecodeCalldata() error = %v", err)
+		}
+		return call.String()
+	}
+
+	// 节点错误时按原始数值显示，并且不缓存
+	if got, want := decode(), fmt.Sprintf("transfer(to=%s, value=12500000)", recipient.Hex()); got != want {
+		t.Errorf("DecodeCalldata() during node error = %s, want %s", got, want)
+	}
+	failing = false
+	if got, want := decode(), fmt.Sprintf("transfer(to=%s, value=12.5 USDT)", recipient.Hex()); got != want {
+		t.Errorf("DecodeCalldata() after node recovered = %s, want %s", got, want)
+	}
+
+	// 调用回滚说明不是代币，结果被缓存
+	calls = 0
+	for i := 0; i < 2; i++ {
+		if _, err := decoder.DecodeCalldata(context.Background(), &other, transfer); err != nil {
+			t.Fatalf("DecodeCalldata() error = %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("reverted decimals queried %d times, want 1", calls)
+	}
+}
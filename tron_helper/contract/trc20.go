@@ -5,11 +5,15 @@ import (
 	"errors"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
 	"github.com/shopspring/decimal"
 	"github.com/web3coderecho/web3_helper/eth_helper/contract/erc20"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
 	tron "github.com/web3coderecho/web3_helper/tron_helper"
 	"github.com/web3coderecho/web3_helper/utils"
 )
@@ -18,7 +22,6 @@ type Trc20 struct {
 	Chain           *tron.Tron
 	ContractAddress string
 	decimals        int64
-	privateKey      string
 }
 
 func NewTrc20(tron *tron.Tron, contractAddress string) *Trc20 {
@@ -28,10 +31,6 @@ func NewTrc20(tron *tron.Tron, contractAddress string) *Trc20 {
 	}
 }
 
-func (t *Trc20) SetPrivateKey(privateKey string) {
-	t.privateKey = privateKey
-}
-
 // 获取 TRC20 小数位数
 func (t *Trc20) Decimals() (int64, error) {
 	if t.decimals > 0 {
@@ -114,33 +113,54 @@ func (t *Trc20) CheckEnergy(from, to string, amount decimal.Decimal) (int64, err
 	return 0, nil
 }
 
-func (t *Trc20) Transfer(from string, to string, amount decimal.Decimal) (string, error) {
-	if t.privateKey == "" {
-		return "", errors.New("privateKey is empty")
+// Transfer 从 signer 对应的地址转出 TRC20 代币
+func (t *Trc20) Transfer(signer eth_interface.HashSigner, to string, amount decimal.Decimal) (string, error) {
+	if signer == nil {
+		return "", errors.New("signer is nil")
+	}
+	transaction, err := t.buildTransfer(utils.EthToTron(signer.Address()), to, amount)
+	if err != nil {
+		return "", err
+	}
+	signTransaction, err := t.Chain.SignTransaction(transaction, signer)
+	if err != nil {
+		return "", err
+	}
+	return t.Chain.SendRawTransaction(signTransaction)
+}
+
+// BuildUnsignedTransfer 在线构造 from 转出 TRC20 代币的未签名交易，expiration 的含义与 Tron.BuildUnsignedTrx 相同
+// 交易用 tron.SignOffline 签名后交给 Tron.Broadcast 发送
+func (t *Trc20) BuildUnsignedTransfer(from, to string, amount decimal.Decimal, expiration time.Duration) (*core.Transaction, error) {
+	transaction, err := t.buildTransfer(from, to, amount)
+	if err != nil {
+		return nil, err
 	}
+	if expiration > 0 {
+		if err := tron.SetExpiration(transaction.Transaction, expiration); err != nil {
+			return nil, err
+		}
+	}
+	return transaction.Transaction, nil
+}
+
+// buildTransfer 构造 from 转出 TRC20 代币的交易
+func (t *Trc20) buildTransfer(from, to string, amount decimal.Decimal) (*api.TransactionExtention, error) {
 	grpcClient := t.Chain.GetGrpcClient()
 	defer grpcClient.Stop()
 	decimals, err := t.Decimals()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	amount = amount.Mul(decimal.NewFromInt(10).Pow(decimal.NewFromInt(decimals)))
 	callData, err := t.DecodeTransfer(to, amount)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	feeLimit, err := t.EstimateGas(from, callData)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	feeLimit = 1000000000
-	transaction, err := grpcClient.TRC20Send(from, to, t.ContractAddress, amount.BigInt(), feeLimit)
-	if err != nil {
-		return "", err
-	}
-	signTransaction, err := t.Chain.SignTransaction(transaction, t.privateKey)
-	if err != nil {
-		return "", err
-	}
-	return t.Chain.SendRawTransaction(signTransaction)
+	return grpcClient.TRC20Send(from, to, t.ContractAddress, amount.BigInt(), feeLimit)
 }
@@ -11,7 +11,6 @@ func TestTrc20_EstimateGas(t1 *testing.T) {
 		Chain           *tron.Tron
 		ContractAddress string
 		decimals        int64
-		privateKey      string
 	}
 	type args struct {
 		from string
@@ -30,7 +29,6 @@ func TestTrc20_EstimateGas(t1 *testing.T) {
 				Chain:           tron.NewTron("", "", ""),
 				ContractAddress: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t",
 				decimals:        6,
-				privateKey:      "",
 			},
 			args: args{
 				from: "TCuwE5apx5WpqAF73Q6sni1pt95BbZ1ymH",
@@ -44,7 +42,6 @@ func TestTrc20_EstimateGas(t1 *testing.T) {
 				Chain:           tt.fields.Chain,
 				ContractAddress: tt.fields.ContractAddress,
 				decimals:        tt.fields.decimals,
-				privateKey:      tt.fields.privateKey,
 			}
 			balance, err := t.BalanceOf("TXyEfK4zD1738nSW1dDq1VtFuTTd7tg1qt")
 			if err != nil {
@@ -0,0 +1,151 @@
+package tron
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/common"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"github.com/golang/protobuf/proto"
+	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+	"github.com/web3coderecho/web3_helper/utils"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// MaxExpiration Tron 交易允许的最长有效期
+const MaxExpiration = 24 * time.Hour
+
+var (
+	// ErrSignerMismatch 签名器地址与交易的发送地址不一致
+	ErrSignerMismatch = errors.New("signer does not match transaction owner")
+	// ErrAlreadySigned 交易已经签名，修改 raw_data 会使签名失效
+	ErrAlreadySigned = errors.New("transaction already signed")
+	// ErrUnverifiableOwner 无法从交易中解析出发送地址，离线签名时拒绝签名
+	ErrUnverifiableOwner = errors.New("cannot verify transaction owner")
+)
+
+// BuildUnsignedTrx 在线构造 from 转出 TRX 的未签名交易
+// 节点返回的交易默认 60 秒后过期，expiration 大于 0 时改为从创建时起 expiration 后过期，最长 MaxExpiration
+func (t *Tron) BuildUnsignedTrx(from, to string, amount decimal.Decimal, expiration time.Duration) (*core.Transaction, error) {
+	grpcClient := t.GetGrpcClient()
+	defer grpcClient.Stop()
+	amount = amount.Mul(decimal.NewFromInt(10).Pow(decimal.NewFromInt(6)))
+	transaction, err := grpcClient.Transfer(from, to, amount.IntPart())
+	if err != nil {
+		return nil, err
+	}
+	if expiration > 0 {
+		if err := SetExpiration(transaction.Transaction, expiration); err != nil {
+			return nil, err
+		}
+	}
+	return transaction.Transaction, nil
+}
+
+// SetExpiration 把未签名交易的过期时间设为创建时间之后 expiration
+func SetExpiration(tx *core.Transaction, expiration time.Duration) error {
+	if len(tx.GetSignature()) > 0 {
+		return ErrAlreadySigned
+	}
+	if expiration > MaxExpiration {
+		return fmt.Errorf("expiration %s exceeds %s", expiration, MaxExpiration)
+	}
+	tx.RawData.Expiration = tx.RawData.Timestamp + expiration.Milliseconds()
+	return nil
+}
+
+// TransactionID 计算交易 ID，即 sha256(raw_data)
+func TransactionID(tx *core.Transaction) ([]byte, error) {
+	rawData, err := proto.Marshal(tx.GetRawData())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal raw data: %v", err)
+	}
+	hash := sha256.Sum256(rawData)
+	return hash[:], nil
+}
+
+// SignOffline 签名交易，使用本地签名器时不需要网络
+// 只签名能够确认发送地址与签名器一致的交易，目前支持 TRX 转账和合约调用
+func SignOffline(tx *core.Transaction, signer eth_interface.HashSigner) (*core.Transaction, error) {
+	owner, err := transactionOwner(tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnverifiableOwner, err)
+	}
+	if owner != utils.EthToTron(signer.Address()) {
+		return nil, fmt.Errorf("%w: want %s", ErrSignerMismatch, owner)
+	}
+	return signTransaction(tx, signer)
+}
+
+// MarshalTransaction 编码为 protobuf 二进制，与节点 BroadcastTransaction 接收的格式相同
+func MarshalTransaction(tx *core.Transaction) ([]byte, error) {
+	return proto.Marshal(tx)
+}
+
+// UnmarshalTransaction 解码 MarshalTransaction 编码的交易
+func UnmarshalTransaction(data []byte) (*core.Transaction, error) {
+	tx := new(core.Transaction)
+	if err := proto.Unmarshal(data, tx); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %v", err)
+	}
+	return tx, nil
+}
+
+// MarshalTransactionJSON 编码为 protobuf JSON
+func MarshalTransactionJSON(tx *core.Transaction) ([]byte, error) {
+	return protojson.Marshal(tx)
+}
+
+// UnmarshalTransactionJSON 解码 MarshalTransactionJSON 编码的交易
+func UnmarshalTransactionJSON(data []byte) (*core.Transaction, error) {
+	tx := new(core.Transaction)
+	if err := protojson.Unmarshal(data, tx); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %v", err)
+	}
+	return tx, nil
+}
+
+// Broadcast 发送 MarshalTransaction 编码的已签名交易，返回交易 ID
+func (t *Tron) Broadcast(rawTx []byte) (string, error) {
+	tx, err := UnmarshalTransaction(rawTx)
+	if err != nil {
+		return "", err
+	}
+	if len(tx.GetSignature()) == 0 {
+		return "", errors.New("transaction is not signed")
+	}
+	txID, err := TransactionID(tx)
+	if err != nil {
+		return "", err
+	}
+	return t.broadcast(tx, txID)
+}
+
+// transactionOwner 返回交易第一个合约的 owner_address
+func transactionOwner(tx *core.Transaction) (string, error) {
+	contracts := tx.GetRawData().GetContract()
+	if len(contracts) == 0 {
+		return "", errors.New("transaction has no contract")
+	}
+	var owner []byte
+	switch contracts[0].GetType() {
+	case core.Transaction_Contract_TransferContract:
+		var contract core.TransferContract
+		if err := contracts[0].GetParameter().UnmarshalTo(&contract); err != nil {
+			return "", err
+		}
+		owner = contract.OwnerAddress
+	case core.Transaction_Contract_TriggerSmartContract:
+		var contract core.TriggerSmartContract
+		if err := contracts[0].GetParameter().UnmarshalTo(&contract); err != nil {
+			return "", err
+		}
+		owner = contract.OwnerAddress
+	default:
+		return "", fmt.Errorf("unsupported contract type %s", contracts[0].GetType())
+	}
+	return common.EncodeCheck(owner), nil
+}
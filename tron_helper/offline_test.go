@@ -0,0 +1,107 @@
+package tron
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fbsobreira/gotron-sdk/pkg/common"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"github.com/web3coderecho/web3_helper/eth_helper/signer"
+	"github.com/web3coderecho/web3_helper/utils"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// newTransferTransaction 构造 owner 转出 1 TRX 的未签名交易
+func newTransferTransaction(t *testing.T, owner string) *core.Transaction {
+	t.Helper()
+	ownerAddress, _ := common.DecodeCheck(owner)
+	toAddress, _ := common.DecodeCheck("TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t")
+	parameter, err := anypb.New(&core.TransferContract{OwnerAddress: ownerAddress, ToAddress: toAddress, Amount: 1000000})
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+	return &core.Transaction{RawData: &core.TransactionRaw{
+		RefBlockBytes: []byte{0x12, 0x34},
+		RefBlockHash:  []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		Timestamp:     1700000000000,
+		Expiration:    1700000060000,
+		Contract: []*core.Transaction_Contract{{
+			Type:      core.Transaction_Contract_TransferContract,
+			Parameter: parameter,
+		}},
+	}}
+}
+
+func TestSignOffline(t *testing.T) {
+	key, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	tx := newTransferTransaction(t, utils.EthToTron(key.Address()))
+	if err := SetExpiration(tx, 2*time.Hour); err != nil {
+		t.Fatalf("SetExpiration() error = %v", err)
+	}
+	if tx.RawData.Expiration != 1700000000000+7200000 {
+		t.Errorf("SetExpiration() expiration = %d", tx.RawData.Expiration)
+	}
+	txID, _ := TransactionID(tx)
+
+	signed, err := SignOffline(tx, key)
+	if err != nil {
+		t.Fatalf("SignOffline() error = %v", err)
+	}
+	pub, err := crypto.SigToPub(txID, signed.Signature[0])
+	if err != nil || crypto.PubkeyToAddress(*pub) != key.Address() {
+		t.Errorf("SignOffline() signature does not recover to %s: %v", key.Address().Hex(), err)
+	}
+	if err := SetExpiration(signed, time.Hour); !errors.Is(err, ErrAlreadySigned) {
+		t.Errorf("SetExpiration() on signed transaction error = %v, want ErrAlreadySigned", err)
+	}
+
+	other := newTransferTransaction(t, "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t")
+	if _, err := SignOffline(other, key); !errors.Is(err, ErrSignerMismatch) {
+		t.Errorf("SignOffline() error = %v, want ErrSignerMismatch", err)
+	}
+
+	// 不支持的合约类型无法确认发送地址，拒绝签名
+	unknown := newTransferTransaction(t, utils.EthToTron(key.Address()))
+	unknown.RawData.Contract[0].Type = core.Transaction_Contract_FreezeBalanceV2Contract
+	if _, err := SignOffline(unknown, key); !errors.Is(err, ErrUnverifiableOwner) || len(unknown.Signature) != 0 {
+		t.Errorf("SignOffline() with unknown contract type error = %v, want ErrUnverifiableOwner", err)
+	}
+}
+
+func TestTransactionSerialization(t *testing.T) {
+	key, _ := signer.NewPrivateKeySignerFromHex("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	tx, err := SignOffline(newTransferTransaction(t, utils.EthToTron(key.Address())), key)
+	if err != nil {
+		t.Fatalf("SignOffline() error = %v", err)
+	}
+	txID, _ := TransactionID(tx)
+
+	raw, err := MarshalTransaction(tx)
+	if err != nil {
+		t.Fatalf("MarshalTransaction() error = %v", err)
+	}
+	fromRaw, err := UnmarshalTransaction(raw)
+	if err != nil {
+		t.Fatalf("UnmarshalTransaction() error = %v", err)
+	}
+	encoded, err := MarshalTransactionJSON(tx)
+	if err != nil {
+		t.Fatalf("MarshalTransactionJSON() error = %v", err)
+	}
+	fromJSON, err := UnmarshalTransactionJSON(encoded)
+	if err != nil {
+		t.Fatalf("UnmarshalTransactionJSON() error = %v", err)
+	}
+	for _, got := range []*core.Transaction{fromRaw, fromJSON} {
+		id, _ := TransactionID(got)
+		if !bytes.Equal(id, txID) || !bytes.Equal(got.Signature[0], tx.Signature[0]) {
+			t.Errorf("decoded transaction %x does not match %x", id, txID)
+		}
+	}
+	if _, err := UnmarshalTransaction([]byte{0xff}); err == nil {
+		t.Errorf("UnmarshalTransaction() with invalid data error = nil, want error")
+	}
+}
@@ -2,7 +2,6 @@ package tron
 
 import (
 	"context"
-	"crypto/sha256"
 	"errors"
 	"fmt"
 	"log"
@@ -13,14 +12,14 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/fbsobreira/gotron-sdk/pkg/client"
 	"github.com/fbsobreira/gotron-sdk/pkg/common"
 	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
 	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
-	"github.com/golang/protobuf/proto"
 	"github.com/shopspring/decimal"
+	"github.com/web3coderecho/web3_helper/eth_helper/eth_interface"
+	"github.com/web3coderecho/web3_helper/utils"
 	"google.golang.org/grpc"
 )
 
@@ -56,7 +55,7 @@ func (t *Tron) GetLogs(ctx context.Context, params map[string]interface{}) []typ
 	grpcClient := t.NewTronJsonRpcClient(ctx)
 	defer grpcClient.Close()
 	if err := grpcClient.CallContext(ctx, &result, "eth_getLogs", params); err != nil {
-		log.Fatalf(fmt.Sprintf("failed to get logs: %v", err))
+		log.Fatalf("failed to get logs: %v", err)
 	}
 	if len(result) > 0 {
 		return result
@@ -161,15 +160,16 @@ func (t *Tron) Balance(address string) (decimal.Decimal, error) {
 	return decimal.NewFromBigInt(big.NewInt(res.Balance), -6), nil
 }
 
-func (t *Tron) TransferTrx(from, to string, amount decimal.Decimal, privateKey string) (string, error) {
+// TransferTrx 从 signer 对应的地址转出 TRX
+func (t *Tron) TransferTrx(signer eth_interface.HashSigner, to string, amount decimal.Decimal) (string, error) {
 	grpcClient := t.GetGrpcClient()
 	defer grpcClient.Stop()
 	amount = amount.Mul(decimal.NewFromInt(10).Pow(decimal.NewFromInt(6)))
-	transaction, err := grpcClient.Transfer(from, to, amount.IntPart())
+	transaction, err := grpcClient.Transfer(utils.EthToTron(signer.Address()), to, amount.IntPart())
 	if err != nil {
 		return "", err
 	}
-	signTransaction, err := t.SignTransaction(transaction, privateKey)
+	signTransaction, err := t.SignTransaction(transaction, signer)
 	if err != nil {
 		return "", err
 	}
@@ -177,37 +177,45 @@ func (t *Tron) TransferTrx(from, to string, amount decimal.Decimal, privateKey s
 }
 
 func (t *Tron) SendRawTransaction(transaction *api.TransactionExtention) (string, error) {
+	return t.broadcast(transaction.Transaction, transaction.GetTxid())
+}
+
+// broadcast 发送已签名的交易，成功时返回不带 0x 前缀的交易 ID
+func (t *Tron) broadcast(transaction *core.Transaction, txID []byte) (string, error) {
 	grpcClient := t.GetGrpcClient()
 	defer grpcClient.Stop()
-	response, err := grpcClient.Broadcast(transaction.Transaction)
+	response, err := grpcClient.Broadcast(transaction)
 	if response == nil {
 		return "", err
 	}
 	if response.Result {
-		return strings.TrimPrefix(common.BytesToHexString(transaction.GetTxid()), "0x"), nil
+		return strings.TrimPrefix(common.BytesToHexString(txID), "0x"), nil
 	} else {
 		return "", errors.New(string(response.Message))
 	}
 }
-func (t *Tron) SignTransaction(transaction *api.TransactionExtention, privateKey string) (*api.TransactionExtention, error) {
-	// 获取交易的 RawData
-	rawData, err := proto.Marshal(transaction.Transaction.GetRawData())
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal raw data: %v", err)
+
+// SignTransaction 使用签名器签名交易，Tron 与以太坊使用相同的 secp256k1 密钥
+// Tron 交易签名的是原始哈希，签名器需要实现 HashSigner，远程签名服务不能用于 Tron
+func (t *Tron) SignTransaction(transaction *api.TransactionExtention, signer eth_interface.HashSigner) (*api.TransactionExtention, error) {
+	if _, err := signTransaction(transaction.Transaction, signer); err != nil {
+		return nil, err
 	}
-	h256h := sha256.New()
-	h256h.Write(rawData)
-	hash := h256h.Sum(nil)
-	// 使用私钥签名
-	privateKeyECDSA, err := crypto.HexToECDSA(privateKey)
+	return transaction, nil
+}
+
+// signTransaction 签名 sha256(raw_data) 并把签名追加到交易中
+func signTransaction(transaction *core.Transaction, signer eth_interface.HashSigner) (*core.Transaction, error) {
+	hash, err := TransactionID(transaction)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert private key: %v", err)
+		return nil, err
 	}
-	signature, err := crypto.Sign(hash, privateKeyECDSA)
+	// 使用签名器签名
+	signature, err := signer.SignHash(context.Background(), hash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign transaction: %v", err)
 	}
 	// 将签名添加到交易中
-	transaction.Transaction.Signature = append(transaction.Transaction.Signature, signature)
+	transaction.Signature = append(transaction.Signature, signature)
 	return transaction, nil
 }